@@ -0,0 +1,69 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AnnotationVisitor is an optional extension to the Visitor interface for
+// visitors that additionally retrieve annotations of nodes, such as sizes,
+// counts, or statuses. Annotations are rendered right of the node labels,
+// optionally lined up in a common column; see TreeStyler.AlignAnnotations.
+type AnnotationVisitor interface {
+	Visitor
+	Annotation(node any) (annotation string, err error)
+}
+
+var _ AnnotationVisitor = (*MapStructVisitor)(nil)
+
+// Annotation returns the annotation of a tree node, taken from a struct field
+// tagged as `asciitree:"annotation"` or from the well-known "annotation" map
+// key. Annotations other than strings are formatted using their default
+// format, such as for sizes and counts.
+func (v *MapStructVisitor) Annotation(node any) (string, error) {
+	node = nodeOf(node)
+	if _, ok := treeNode(node); ok {
+		// TreeNodes don't have annotations.
+		return "", nil
+	}
+	var annotationV reflect.Value
+	switch node := reflect.Indirect(reflect.ValueOf(node)); node.Kind() {
+	case reflect.Struct:
+		si := structFieldInfo(node)
+		if si.AnnotationPath == nil {
+			return "", nil
+		}
+		annotationV = node.FieldByIndex(si.AnnotationPath)
+	case reflect.Map:
+		if !hasStringKeys(node) {
+			return "", newNodeError(ErrUnsupportedNodeType, anyOf(node))
+		}
+		annotationV = mapValue(node, "annotation")
+	default:
+		return "", newNodeError(ErrUnsupportedNodeType, anyOf(node))
+	}
+	if annotationV.Kind() == reflect.Interface {
+		annotationV = annotationV.Elem()
+	}
+	if !annotationV.IsValid() {
+		return "", nil
+	}
+	if annotationV.Kind() == reflect.String {
+		return annotationV.String(), nil
+	}
+	return fmt.Sprint(annotationV.Interface()), nil
+}
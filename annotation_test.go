@@ -0,0 +1,131 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("annotations", func() {
+
+	type FileNode struct {
+		Name     string     `asciitree:"label"`
+		Size     int        `asciitree:"annotation"`
+		Children []FileNode `asciitree:"children"`
+	}
+
+	tree := FileNode{Name: "/", Size: 4096, Children: []FileNode{
+		{Name: "etc", Size: 42, Children: []FileNode{
+			{Name: "passwd", Size: 1234},
+		}},
+		{Name: "tmp", Size: 0},
+	}}
+
+	When("visiting", func() {
+
+		It("gets nothing from annotation-less nodes", func() {
+			Expect(DefaultVisitor.Annotation(Node{Name: "foo"})).To(BeEmpty())
+			Expect(DefaultVisitor.Annotation(map[string]any{"label": "foo"})).To(BeEmpty())
+			Expect(DefaultVisitor.Annotation(map[string]any{"annotation": nil})).To(BeEmpty())
+		})
+
+		It("gets annotations", func() {
+			Expect(DefaultVisitor.Annotation(&tree)).To(Equal("4096"))
+			Expect(DefaultVisitor.Annotation(map[string]any{"annotation": "ok"})).To(Equal("ok"))
+			Expect(DefaultVisitor.Annotation(map[string]any{"annotation": 1.5})).To(Equal("1.5"))
+		})
+
+		It("reports unsupported nodes", func() {
+			_, err := DefaultVisitor.Annotation(42)
+			Expect(err).To(MatchError(ErrUnsupportedNodeType))
+		})
+
+	})
+
+	When("rendering", func() {
+
+		It("renders annotations right of labels", func() {
+			Expect(Render(tree, DefaultVisitor, DefaultTreeStyler)).To(Equal(`/  4096
++- etc  42
+|  ` + "`" + `- passwd  1234
+` + "`" + `- tmp  0
+`))
+		})
+
+		It("lines up annotations", func() {
+			ats := NewTreeStyler(LineStyle)
+			ats.AlignAnnotations = true
+			Expect(Render(tree, DefaultVisitor, ats)).To(Equal(
+				"/" + strings.Repeat(" ", 13) + "4096\n" +
+					"├─ etc        42\n" +
+					"│  └─ passwd  1234\n" +
+					"└─ tmp        0\n"))
+		})
+
+		It("renders annotations only on the first label line", func() {
+			ats := NewTreeStyler(ASCIIStyle)
+			ats.AlignAnnotations = true
+			Expect(Render(map[string]any{
+				"label":      "foo\nbar baz",
+				"annotation": "A",
+				"properties": []string{"prop"},
+				"children": []any{
+					map[string]any{"label": "x"},
+				},
+			}, DefaultVisitor, ats)).To(Equal(`foo  A
+bar baz
+|  * prop
+` + "`" + `- x
+`))
+		})
+
+		It("styles annotations", func() {
+			ats := NewTreeStyler(ASCIIStyle)
+			ats.AnnotationStyle = Dim
+			ats.Color = ColorAlways
+			Expect(Render(map[string]any{"label": "foo", "annotation": "A"}, DefaultVisitor, ats)).To(
+				Equal("foo  \x1b[2mA\x1b[0m\n"))
+			ats.Color = ColorNever
+			Expect(Render(map[string]any{"label": "foo", "annotation": "A"}, DefaultVisitor, ats)).To(
+				Equal("foo  A\n"))
+		})
+
+		It("doesn't leak annotations into line information", func() {
+			ats := NewTreeStyler(ASCIIStyle)
+			for _, align := range []bool{false, true} {
+				ats.AlignAnnotations = align
+				for line, info := range NodeLines(tree, DefaultVisitor, ats) {
+					Expect(info.annotation).To(BeEmpty(), "line %q", line)
+				}
+			}
+		})
+
+		It("stops early", func() {
+			ats := NewTreeStyler(ASCIIStyle)
+			ats.AlignAnnotations = true
+			count := 0
+			for range Lines(tree, DefaultVisitor, ats) {
+				count++
+				break
+			}
+			Expect(count).To(Equal(1))
+		})
+
+	})
+
+})
@@ -0,0 +1,144 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// KeyValue is a single key-value attribute of a node.
+type KeyValue struct {
+	Key   string
+	Value any
+}
+
+// AttributeVisitor is an optional extension to the Visitor interface for
+// visitors that additionally retrieve key-value attributes of nodes. The
+// attributes of a node are rendered after its properties in form of "key:
+// value" properties, with the values of a node lined up in a common column.
+type AttributeVisitor interface {
+	Visitor
+	Attributes(node any) (attributes []KeyValue, err error)
+}
+
+var _ AttributeVisitor = (*MapStructVisitor)(nil)
+
+// Attributes returns the key-value attributes of a tree node, taken from a
+// struct field tagged as `asciitree:"attributes"` or from the well-known
+// "attributes" map key. The attributes must be either a map with string keys,
+// such as map[string]any, or a []KeyValue. Map attributes are sorted by
+// their keys, while []KeyValue attributes keep their order unless the visitor
+// sorts properties.
+func (v *MapStructVisitor) Attributes(node any) ([]KeyValue, error) {
+	return v.attributes(node, false)
+}
+
+// attributes returns the key-value attributes of a tree node, optionally
+// ignoring attributes of map nodes of an unsupported type.
+func (v *MapStructVisitor) attributes(node any, lenient bool) ([]KeyValue, error) {
+	node = nodeOf(node)
+	if _, ok := treeNode(node); ok {
+		// TreeNodes don't have attributes.
+		return nil, nil
+	}
+	var label string
+	var attrsV reflect.Value
+	mapNode := false
+	switch node := reflect.Indirect(reflect.ValueOf(node)); node.Kind() {
+	case reflect.Struct:
+		si := structFieldInfo(node)
+		if si.AttributesPath == nil {
+			return nil, nil
+		}
+		label = structLabel(node, si)
+		attrsV = node.FieldByIndex(si.AttributesPath)
+	case reflect.Map:
+		if !hasStringKeys(node) {
+			return nil, newNodeError(ErrUnsupportedNodeType, anyOf(node))
+		}
+		attrsV = mapValue(node, "attributes")
+		if !attrsV.IsValid() {
+			return nil, nil
+		}
+		label = mapLabel(node)
+		mapNode = true
+	default:
+		return nil, newNodeError(ErrUnsupportedNodeType, anyOf(node))
+	}
+	attrs, err := keyValues(attrsV, label)
+	if err != nil {
+		if lenient && mapNode {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if v.SortProperties {
+		attrs = slices.Clone(attrs)
+		slices.SortStableFunc(attrs, func(a, b KeyValue) int {
+			return strings.Compare(a.Key, b.Key)
+		})
+	}
+	return attrs, nil
+}
+
+// keyValues returns the key-value attributes contained in the passed
+// reflect.Value (unpacking an interface value where necessary), with map
+// attributes sorted by their keys. It returns nil for a nil value, but an
+// ErrBadAttributesField error for any other type than []KeyValue or a map
+// with string keys.
+func keyValues(v reflect.Value, label string) ([]KeyValue, error) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if attrs, ok := v.Interface().([]KeyValue); ok {
+		return attrs, nil
+	}
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		err := newNodeError(ErrBadAttributesField, v.Interface())
+		err.Path = []string{label}
+		return nil, err
+	}
+	attrs := make([]KeyValue, 0, v.Len())
+	for iter := v.MapRange(); iter.Next(); {
+		attrs = append(attrs, KeyValue{Key: iter.Key().String(), Value: iter.Value().Interface()})
+	}
+	slices.SortFunc(attrs, func(a, b KeyValue) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return attrs, nil
+}
+
+// formatAttributes returns the passed attributes formatted as "key: value"
+// properties, with the keys padded to a common display width so that the
+// values line up.
+func formatAttributes(attrs []KeyValue) []string {
+	keyWidth := 0
+	for _, attr := range attrs {
+		keyWidth = max(keyWidth, StringWidth(attr.Key))
+	}
+	props := make([]string, len(attrs))
+	for idx, attr := range attrs {
+		props[idx] = attr.Key + ":" +
+			strings.Repeat(" ", keyWidth-StringWidth(attr.Key)+1) +
+			fmt.Sprint(attr.Value)
+	}
+	return props
+}
@@ -0,0 +1,149 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("attributes", func() {
+
+	type AttrNode struct {
+		Name     string         `asciitree:"label"`
+		Props    []string       `asciitree:"properties"`
+		Attrs    map[string]any `asciitree:"attributes"`
+		Children []AttrNode     `asciitree:"children"`
+	}
+
+	type KVNode struct {
+		Name  string     `asciitree:"label"`
+		Attrs []KeyValue `asciitree:"attributes"`
+	}
+
+	It("formats attributes with aligned values", func() {
+		Expect(formatAttributes(nil)).To(BeEmpty())
+		Expect(formatAttributes([]KeyValue{
+			{Key: "name", Value: "foo"},
+			{Key: "id", Value: 42},
+			{Key: "世界", Value: true},
+		})).To(HaveExactElements(
+			"name: foo",
+			"id:   42",
+			"世界: true"))
+	})
+
+	When("visiting", func() {
+
+		It("gets nothing from attribute-less nodes", func() {
+			Expect(DefaultVisitor.Attributes(Node{Name: "foo"})).To(BeEmpty())
+			Expect(DefaultVisitor.Attributes(map[string]any{"label": "foo"})).To(BeEmpty())
+			Expect(DefaultVisitor.Attributes(AttrNode{Name: "foo"})).To(BeEmpty())
+		})
+
+		It("gets sorted map attributes from structs", func() {
+			Expect(DefaultVisitor.Attributes(&AttrNode{
+				Attrs: map[string]any{"zz": 1, "aa": "2"},
+			})).To(HaveExactElements(
+				KeyValue{Key: "aa", Value: "2"},
+				KeyValue{Key: "zz", Value: 1}))
+		})
+
+		It("gets attributes in order from maps", func() {
+			attrs := []KeyValue{{Key: "zz", Value: 1}, {Key: "aa", Value: 2}}
+			Expect(DefaultVisitor.Attributes(map[string]any{"attributes": attrs})).To(
+				Equal(attrs))
+			Expect(DefaultVisitor.Attributes(KVNode{Attrs: attrs})).To(
+				Equal(attrs))
+			Expect(DefaultVisitor.Attributes(map[string]any{
+				"attributes": map[string]string{"zz": "1", "aa": "2"},
+			})).To(HaveExactElements(
+				KeyValue{Key: "aa", Value: "2"},
+				KeyValue{Key: "zz", Value: "1"}))
+		})
+
+		It("sorts attributes when sorting properties", func() {
+			attrs := []KeyValue{{Key: "zz", Value: 1}, {Key: "aa", Value: 2}}
+			Expect(NewMapStructVisitor(false, true).Attributes(KVNode{Attrs: attrs})).To(
+				HaveExactElements(attrs[1], attrs[0]))
+			Expect(attrs[0].Key).To(Equal("zz"))
+		})
+
+		It("reports bad attributes", func() {
+			_, err := DefaultVisitor.Attributes(map[string]any{
+				"label":      "foo",
+				"attributes": []string{"bar"},
+			})
+			Expect(err).To(MatchError(ErrBadAttributesField))
+			var nerr *NodeError
+			Expect(errors.As(err, &nerr)).To(BeTrue())
+			Expect(nerr.Path).To(HaveExactElements("foo"))
+
+			_, err = DefaultVisitor.Attributes(42)
+			Expect(err).To(MatchError(ErrUnsupportedNodeType))
+		})
+
+	})
+
+	When("rendering", func() {
+
+		It("renders attributes after properties", func() {
+			tree := AttrNode{
+				Name:  "root",
+				Props: []string{"prop"},
+				Attrs: map[string]any{"name": "foo", "id": 42},
+				Children: []AttrNode{
+					{Name: "child", Attrs: map[string]any{"x": 1, "longer": 2}},
+				},
+			}
+			Expect(Render(tree, DefaultVisitor, DefaultTreeStyler)).To(Equal(`root
+|  * prop
+|  * id:   42
+|  * name: foo
+` + "`" + `- child
+      * longer: 2
+      * x:      1
+`))
+		})
+
+		It("doesn't modify the properties of nodes", func() {
+			props := make([]string, 1, 10)
+			props[0] = "prop"
+			tree := map[string]any{
+				"label":      "root",
+				"properties": props,
+				"attributes": map[string]any{"foo": "bar"},
+			}
+			Expect(Render(tree, DefaultVisitor, DefaultTreeStyler)).To(Equal(
+				"root\n   * prop\n   * foo: bar\n"))
+			Expect(props[:2]).To(HaveExactElements("prop", ""))
+		})
+
+		It("reports bad attributes with their path", func() {
+			tree := map[string]any{
+				"label": "root",
+				"children": []any{
+					map[string]any{"label": "child", "attributes": 42},
+				},
+			}
+			_, err := TryRender(tree, DefaultVisitor, DefaultTreeStyler)
+			Expect(err).To(MatchError(`unsupported asciitree attributes type int at ["root" "child"]`))
+		})
+
+	})
+
+})
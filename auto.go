@@ -0,0 +1,63 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// AutoTreeStyler returns a new TreeStyler suitable for rendering into the
+// passed writer: it uses the Unicode LineStyle when rendering into a terminal
+// with a UTF-8 locale, and the pure ASCIIStyle otherwise. A nil writer
+// indicates an unknown destination, such as when rendering into a string, so
+// that only the environment is taken into account. See also AutoStyle.
+func AutoTreeStyler(w io.Writer) *TreeStyler {
+	return NewTreeStyler(AutoStyle(w, os.Getenv))
+}
+
+// AutoStyle returns LineStyle if the passed writer is a terminal (or unknown
+// when nil) and the environment indicates a UTF-8 locale, otherwise
+// ASCIIStyle. The environment variables are looked up using the passed
+// getenv function, such as os.Getenv. The locale is taken from the first
+// non-empty variable of LC_ALL, LC_CTYPE, and LANG. A TERM of "dumb" always
+// results in ASCIIStyle.
+func AutoStyle(w io.Writer, getenv func(key string) string) TreeStyle {
+	if getenv("TERM") == "dumb" {
+		return ASCIIStyle
+	}
+	if w != nil && !isTerminal(w) {
+		return ASCIIStyle
+	}
+	if !isUTF8Locale(getenv) {
+		return ASCIIStyle
+	}
+	return LineStyle
+}
+
+// isUTF8Locale returns true if the locale set in the environment uses the
+// UTF-8 character encoding, such as "en_US.UTF-8" or "C.utf8".
+func isUTF8Locale(getenv func(key string) string) bool {
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		locale := getenv(key)
+		if locale == "" {
+			continue
+		}
+		locale = strings.ToLower(locale)
+		return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// env returns a getenv function looking up the passed variables.
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+var _ = Describe("automatic tree style", func() {
+
+	// As we cannot portably get hold of a terminal in tests, we use an unknown
+	// destination instead, which AutoStyle treats like a terminal.
+	DescribeTable("choosing the tree style",
+		func(vars map[string]string, toTerminal bool, expected TreeStyle) {
+			var w io.Writer = &bytes.Buffer{}
+			if toTerminal {
+				w = nil
+			}
+			Expect(AutoStyle(w, env(vars))).To(Equal(expected))
+		},
+		Entry("UTF-8 terminal", map[string]string{"LANG": "en_US.UTF-8"}, true, LineStyle),
+		Entry("utf8 terminal", map[string]string{"LC_CTYPE": "C.utf8"}, true, LineStyle),
+		Entry("UTF-8 non-terminal", map[string]string{"LANG": "en_US.UTF-8"}, false, ASCIIStyle),
+		Entry("no locale", map[string]string{}, true, ASCIIStyle),
+		Entry("non-UTF-8 locale", map[string]string{"LANG": "de_DE.ISO-8859-1"}, true, ASCIIStyle),
+		Entry("LC_ALL overriding LANG", map[string]string{"LC_ALL": "C", "LANG": "en_US.UTF-8"}, true, ASCIIStyle),
+		Entry("LC_ALL overriding LC_CTYPE", map[string]string{"LC_ALL": "en_US.UTF-8", "LC_CTYPE": "C"}, true, LineStyle),
+		Entry("LC_CTYPE overriding LANG", map[string]string{"LC_CTYPE": "POSIX", "LANG": "en_US.UTF-8"}, true, ASCIIStyle),
+		Entry("dumb terminal", map[string]string{"LANG": "en_US.UTF-8", "TERM": "dumb"}, true, ASCIIStyle),
+	)
+
+	It("takes only the environment into account for unknown destinations", func() {
+		Expect(AutoStyle(nil, env(map[string]string{"LANG": "en_US.UTF-8"}))).To(Equal(LineStyle))
+		Expect(AutoStyle(nil, env(map[string]string{"LANG": "C"}))).To(Equal(ASCIIStyle))
+	})
+
+	It("returns a tree styler", func() {
+		setenv("LC_ALL", "en_US.UTF-8")
+		setenv("TERM", "xterm")
+		Expect(AutoTreeStyler(nil).Style).To(Equal(LineStyle))
+		Expect(AutoTreeStyler(&bytes.Buffer{}).Style).To(Equal(ASCIIStyle))
+	})
+
+	It("doesn't treat other character devices as terminals", func() {
+		devnull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		Expect(err).NotTo(HaveOccurred())
+		defer devnull.Close()
+		Expect(AutoStyle(devnull, env(map[string]string{"LANG": "en_US.UTF-8"}))).To(Equal(ASCIIStyle))
+	})
+
+})
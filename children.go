@@ -0,0 +1,146 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"cmp"
+	"container/list"
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ChildIterator can be implemented by user-defined collections of child
+// nodes, so that a MapStructVisitor can iterate over the child nodes stored
+// in such collections.
+type ChildIterator interface {
+	Children() iter.Seq[any]
+}
+
+// KeyedNode is a child node taken from a map or an iter.Seq2, together with
+// its key that becomes the label of the node. A MapStructVisitor produces
+// keyed nodes for children fields tagged as `asciitree:"children,keylabels"`.
+// Please note that such keyed nodes are passed to node stylers, as well as in
+// line information and to table columns.
+type KeyedNode struct {
+	Key  string // key of the node, used as its label.
+	Node any    // the node itself.
+}
+
+// nodeOf returns the node wrapped in a KeyedNode, or the passed node itself.
+func nodeOf(node any) any {
+	if kn, ok := node.(KeyedNode); ok {
+		return kn.Node
+	}
+	return node
+}
+
+// anyNodes returns the child nodes contained in the passed reflect.Value
+// (unpacking an interface value where necessary). The child nodes can be
+// stored in slices, arrays, maps (ordered by their keys), iter.Seq and
+// iter.Seq2 iterators, channels, container/list lists, and collections
+// implementing ChildIterator. For maps and iter.Seq2 iterators, the keys
+// become the node labels if keyLabels is true, wrapping the nodes into
+// KeyedNodes. anyNodes returns nil for any other value.
+//
+// Please note that channels need to be closed by their senders, as otherwise
+// anyNodes blocks.
+func anyNodes(v reflect.Value, keyLabels bool) []any {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	if v.CanInterface() {
+		switch children := v.Interface().(type) {
+		case ChildIterator:
+			return slices.Collect(children.Children())
+		case *list.List:
+			nodes := make([]any, 0, children.Len())
+			for el := children.Front(); el != nil; el = el.Next() {
+				nodes = append(nodes, el.Value)
+			}
+			return nodes
+		}
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		nodes := make([]any, v.Len())
+		for idx := range nodes {
+			nodes[idx] = v.Index(idx).Interface()
+		}
+		return nodes
+	case reflect.Map:
+		keys := v.MapKeys()
+		slices.SortFunc(keys, compareKeys)
+		nodes := make([]any, len(keys))
+		for idx, key := range keys {
+			nodes[idx] = keyed(key, v.MapIndex(key), keyLabels)
+		}
+		return nodes
+	case reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			return nil
+		}
+		var nodes []any
+		switch {
+		case v.Type().CanSeq2():
+			for key, node := range v.Seq2() {
+				nodes = append(nodes, keyed(key, node, keyLabels))
+			}
+		case v.Type().CanSeq():
+			for node := range v.Seq() {
+				nodes = append(nodes, node.Interface())
+			}
+		}
+		return nodes
+	}
+	return nil
+}
+
+// keyed returns the passed node, wrapped into a KeyedNode together with its
+// key if keyLabels is true.
+func keyed(key reflect.Value, node reflect.Value, keyLabels bool) any {
+	if !keyLabels {
+		return node.Interface()
+	}
+	return KeyedNode{Key: formatLabel(key, ""), Node: node.Interface()}
+}
+
+// compareKeys compares two map keys, ordering keys of ordered kinds by their
+// values, and other keys by their textual representations.
+func compareKeys(a, b reflect.Value) int {
+	if a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	if b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+	if a.Kind() == b.Kind() {
+		switch a.Kind() {
+		case reflect.String:
+			return strings.Compare(a.String(), b.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(a.Int(), b.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return cmp.Compare(a.Uint(), b.Uint())
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(a.Float(), b.Float())
+		}
+	}
+	return strings.Compare(formatLabel(a, ""), formatLabel(b, ""))
+}
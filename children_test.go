@@ -0,0 +1,133 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"container/list"
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// childSet is a user-defined collection of child nodes.
+type childSet struct {
+	nodes []any
+}
+
+func (c childSet) Children() iter.Seq[any] { return slices.Values(c.nodes) }
+
+var _ = Describe("children", func() {
+
+	DescribeTable("getting child nodes",
+		func(children any, keyLabels bool, expected []any) {
+			Expect(anyNodes(reflect.ValueOf(children), keyLabels)).To(Equal(expected))
+		},
+		Entry("nil", nil, false, nil),
+		Entry("scalar", 42, false, nil),
+		Entry("slice", []string{"a", "b"}, false, []any{"a", "b"}),
+		Entry("array", [2]int{1, 2}, false, []any{1, 2}),
+		Entry("string-keyed map", map[string]int{"b": 2, "a": 1, "c": 3}, false, []any{1, 2, 3}),
+		Entry("int-keyed map", map[int]string{10: "ten", 9: "nine"}, false, []any{"nine", "ten"}),
+		Entry("keyed map", map[int]string{10: "ten", 9: "nine"}, true, []any{
+			KeyedNode{Key: "9", Node: "nine"},
+			KeyedNode{Key: "10", Node: "ten"},
+		}),
+		Entry("mixed keys map", map[any]int{"a": 1, 2: 2}, false, []any{2, 1}),
+		Entry("iter.Seq", slices.Values([]string{"a", "b"}), false, []any{"a", "b"}),
+		Entry("nil iter.Seq", iter.Seq[string](nil), false, nil),
+		Entry("iter.Seq2", maps.All(map[string]int{"a": 1}), false, []any{1}),
+		Entry("keyed iter.Seq2", slices.All([]string{"a"}), true, []any{KeyedNode{Key: "0", Node: "a"}}),
+		Entry("child iterator", childSet{nodes: []any{"a", 42}}, false, []any{"a", 42}),
+	)
+
+	It("gets child nodes from channels", func() {
+		ch := make(chan string, 2)
+		ch <- "a"
+		ch <- "b"
+		close(ch)
+		Expect(anyNodes(reflect.ValueOf(ch), false)).To(HaveExactElements("a", "b"))
+	})
+
+	It("gets child nodes from lists", func() {
+		l := list.New()
+		l.PushBack("a")
+		l.PushBack(42)
+		Expect(anyNodes(reflect.ValueOf(l), false)).To(HaveExactElements("a", 42))
+	})
+
+	When("rendering", func() {
+
+		type Dir struct {
+			Name    string          `asciitree:"label"`
+			Entries map[string]*Dir `asciitree:"children,keylabels"`
+		}
+
+		It("renders map children with keys as labels", func() {
+			root := &Dir{Name: "/", Entries: map[string]*Dir{
+				"usr": {Entries: map[string]*Dir{"bin": {}, "lib": {}}},
+				"etc": {},
+			}}
+			root.Entries["usr"].Entries["up"] = root
+			Expect(Render(root, DefaultVisitor, DefaultTreeStyler)).To(Equal(`/
++- etc
+` + "`" + `- usr
+   +- bin
+   +- lib
+   ` + "`" + `- ↻ (cycle to /)
+`))
+		})
+
+		It("unwraps keyed nodes", func() {
+			node := KeyedNode{Key: "key", Node: map[string]any{
+				"label":      "label",
+				"properties": []string{"prop"},
+				"attributes": map[string]any{"foo": "bar"},
+				"annotation": "note",
+				"children":   []any{map[string]any{"label": "child"}},
+			}}
+			Expect(Render(node, DefaultVisitor, DefaultTreeStyler)).To(Equal(`key  note
+|  * prop
+|  * foo: bar
+` + "`" + `- child
+`))
+		})
+
+		It("renders children from iterators and lists", func() {
+			type Node struct {
+				Label    string          `asciitree:"label"`
+				Children iter.Seq[*Node] `asciitree:"children"`
+			}
+			l := list.New()
+			l.PushBack(map[string]any{"label": "b"})
+			l.PushBack(map[string]any{"label": "a"})
+			tree := &Node{Label: "root", Children: slices.Values([]*Node{
+				{Label: "seq"},
+			})}
+			Expect(Render([]any{tree, map[string]any{"label": "list", "children": l}},
+				NewMapStructVisitor(true, false), DefaultTreeStyler)).To(Equal(`list
++- a
+` + "`" + `- b
+root
+` + "`" + `- seq
+`))
+		})
+
+	})
+
+})
@@ -0,0 +1,172 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// TextStyle specifies colors and text attributes in form of ANSI SGR (“Select
+// Graphic Rendition”) parameters, such as "1" for bold or "31" for red. Use
+// Styles to combine multiple text styles, such as Styles(Bold, Red).
+type TextStyle string
+
+// Frequently used text attributes and colors.
+const (
+	Bold      TextStyle = "1"
+	Dim       TextStyle = "2"
+	Italic    TextStyle = "3"
+	Underline TextStyle = "4"
+	Reverse   TextStyle = "7"
+
+	Black   TextStyle = "30"
+	Red     TextStyle = "31"
+	Green   TextStyle = "32"
+	Yellow  TextStyle = "33"
+	Blue    TextStyle = "34"
+	Magenta TextStyle = "35"
+	Cyan    TextStyle = "36"
+	White   TextStyle = "37"
+
+	BrightBlack   TextStyle = "90"
+	BrightRed     TextStyle = "91"
+	BrightGreen   TextStyle = "92"
+	BrightYellow  TextStyle = "93"
+	BrightBlue    TextStyle = "94"
+	BrightMagenta TextStyle = "95"
+	BrightCyan    TextStyle = "96"
+	BrightWhite   TextStyle = "97"
+)
+
+// Styles combines the passed text styles into a single text style.
+func Styles(styles ...TextStyle) TextStyle {
+	params := make([]string, 0, len(styles))
+	for _, style := range styles {
+		if style != "" {
+			params = append(params, string(style))
+		}
+	}
+	return TextStyle(strings.Join(params, ";"))
+}
+
+// Apply returns the passed text wrapped in the ANSI escape sequences for this
+// text style, resetting all text attributes at the end. An empty text style or
+// empty text are returned unchanged.
+func (t TextStyle) Apply(text string) string {
+	if t == "" || text == "" {
+		return text
+	}
+	return "\x1b[" + string(t) + "m" + text + resetStyles
+}
+
+// resetStyles is the SGR escape sequence resetting all text attributes.
+const resetStyles = "\x1b[0m"
+
+// ColorMode controls whether text styles are rendered, or not.
+type ColorMode int
+
+// The color modes.
+const (
+	// ColorAuto renders text styles unless the NO_COLOR environment variable
+	// is set to a non-empty value, or when rendering into a writer that isn't
+	// a terminal.
+	ColorAuto   ColorMode = iota
+	ColorAlways           // always render text styles.
+	ColorNever            // never render text styles.
+)
+
+// colorsEnabled returns true if text styles should be rendered into the passed
+// writer, taking the color mode, the NO_COLOR environment variable, and the
+// writer into account. A nil writer indicates an unknown destination, such as
+// when rendering into a string.
+func colorsEnabled(mode ColorMode, w io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return w == nil || isTerminal(w)
+}
+
+// isTerminal returns true if the passed writer is a terminal. Other character
+// devices, such as /dev/null, are not terminals.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// stripEscapes returns the passed text without any ANSI escape sequences.
+func stripEscapes(text string) string {
+	if strings.IndexByte(text, escape) < 0 {
+		return text
+	}
+	var stripped strings.Builder
+	for len(text) != 0 {
+		if text[0] == escape {
+			text = text[escapeLen(text):]
+			continue
+		}
+		idx := strings.IndexByte(text, escape)
+		if idx < 0 {
+			idx = len(text)
+		}
+		stripped.WriteString(text[:idx])
+		text = text[idx:]
+	}
+	return stripped.String()
+}
+
+// escape starts ANSI escape sequences.
+const escape = '\x1b'
+
+// escapeLen returns the length in bytes of the ANSI escape sequence at the
+// beginning of the passed text. It supports CSI sequences (such as SGR
+// sequences), OSC sequences (such as hyperlinks) terminated by either BEL or
+// ST, and two-byte escape sequences.
+func escapeLen(text string) int {
+	if len(text) < 2 {
+		return len(text)
+	}
+	switch text[1] {
+	case '[':
+		// CSI: parameter and intermediate bytes, terminated by a final byte.
+		for idx := 2; idx < len(text); idx++ {
+			if text[idx] >= 0x40 && text[idx] <= 0x7e {
+				return idx + 1
+			}
+		}
+		return len(text)
+	case ']':
+		// OSC: terminated by either BEL or ST (ESC \).
+		for idx := 2; idx < len(text); idx++ {
+			switch {
+			case text[idx] == '\a':
+				return idx + 1
+			case text[idx] == escape && idx+1 < len(text) && text[idx+1] == '\\':
+				return idx + 2
+			}
+		}
+		return len(text)
+	}
+	return 2
+}
@@ -0,0 +1,164 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"bytes"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// setenv sets the specified environment variable for the duration of the
+// current spec, restoring its original state afterwards.
+func setenv(name, value string) {
+	GinkgoHelper()
+	orig, ok := os.LookupEnv(name)
+	DeferCleanup(func() {
+		if ok {
+			_ = os.Setenv(name, orig)
+			return
+		}
+		_ = os.Unsetenv(name)
+	})
+	Expect(os.Setenv(name, value)).To(Succeed())
+}
+
+var _ = Describe("colors", func() {
+
+	It("combines and applies text styles", func() {
+		Expect(Styles()).To(BeEmpty())
+		Expect(Styles(Bold, "", Red)).To(Equal(TextStyle("1;31")))
+		Expect(Styles(Bold, Red).Apply("foo")).To(Equal("\x1b[1;31mfoo\x1b[0m"))
+		Expect(Red.Apply("")).To(BeEmpty())
+		Expect(TextStyle("").Apply("foo")).To(Equal("foo"))
+	})
+
+	DescribeTable("measuring escape sequences",
+		func(text string, expected int) {
+			Expect(escapeLen(text)).To(Equal(expected))
+		},
+		Entry("lone ESC", "\x1b", 1),
+		Entry("SGR", "\x1b[1;31mfoo", 7),
+		Entry("unterminated CSI", "\x1b[1;31", 6),
+		Entry("OSC with BEL", "\x1b]8;;http://foo\afoo", 16),
+		Entry("OSC with ST", "\x1b]8;;http://foo\x1b\\foo", 17),
+		Entry("unterminated OSC", "\x1b]8;;", 5),
+		Entry("two-byte sequence", "\x1bcfoo", 2),
+	)
+
+	DescribeTable("stripping escape sequences",
+		func(text string, expected string) {
+			Expect(stripEscapes(text)).To(Equal(expected))
+		},
+		Entry(nil, "foo", "foo"),
+		Entry(nil, Red.Apply("foo")+" bar", "foo bar"),
+		Entry(nil, "\x1b]8;;http://foo\afoo\x1b]8;;\a", "foo"),
+	)
+
+	It("ignores escape sequences when measuring display widths", func() {
+		Expect(StringWidth(Red.Apply("foo"))).To(Equal(3))
+		Expect(StringWidth("\x1b]8;;http://foo\afoo\x1b]8;;\a")).To(Equal(3))
+	})
+
+	When("deciding whether to render text styles", func() {
+
+		It("honors the color mode", func() {
+			setenv("NO_COLOR", "1")
+			Expect(colorsEnabled(ColorAlways, &bytes.Buffer{})).To(BeTrue())
+			Expect(colorsEnabled(ColorNever, nil)).To(BeFalse())
+		})
+
+		It("honors NO_COLOR", func() {
+			setenv("NO_COLOR", "")
+			Expect(colorsEnabled(ColorAuto, nil)).To(BeTrue())
+			setenv("NO_COLOR", "1")
+			Expect(colorsEnabled(ColorAuto, nil)).To(BeFalse())
+		})
+
+		It("renders text styles only into terminals", func() {
+			setenv("NO_COLOR", "")
+			Expect(colorsEnabled(ColorAuto, &bytes.Buffer{})).To(BeFalse())
+			f, err := os.CreateTemp(GinkgoT().TempDir(), "nocolor")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(colorsEnabled(ColorAuto, f)).To(BeFalse())
+			Expect(f.Close()).To(Succeed())
+			Expect(isTerminal(f)).To(BeFalse())
+
+			devnull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+			Expect(err).NotTo(HaveOccurred())
+			defer devnull.Close()
+			Expect(isTerminal(devnull)).To(BeFalse())
+			Expect(colorsEnabled(ColorAuto, devnull)).To(BeFalse())
+		})
+
+	})
+
+	When("rendering", func() {
+
+		type M map[string]any
+		tree := M{
+			"label":      "root",
+			"properties": []string{"prop"},
+			"children": []M{
+				{"label": "1"},
+				{"label": "2"},
+			},
+		}
+
+		var cts *TreeStyler
+
+		BeforeEach(func() {
+			setenv("NO_COLOR", "")
+			cts = NewTreeStyler(ASCIIStyle)
+			cts.BranchStyle = Dim
+			cts.LabelStyle = Bold
+			cts.PropertyStyle = Green
+			cts.MarkerStyle = Italic
+			cts.MaxChildren = 1
+		})
+
+		It("renders text styles", func() {
+			Expect(Render(tree, DefaultVisitor, cts)).To(Equal(
+				"\x1b[1mroot\x1b[0m\n" +
+					"\x1b[2m|\x1b[0m  \x1b[2m*\x1b[0m \x1b[32mprop\x1b[0m\n" +
+					"\x1b[2m+-\x1b[0m \x1b[1m1\x1b[0m\n" +
+					"\x1b[2m`-\x1b[0m \x1b[3m… and 1 more\x1b[0m\n"))
+		})
+
+		It("doesn't render text styles into non-terminals or when disabled", func() {
+			var buf bytes.Buffer
+			Expect(RenderTo(&buf, tree, DefaultVisitor, cts)).To(Succeed())
+			Expect(buf.String()).To(Equal("root\n|  * prop\n+- 1\n`- … and 1 more\n"))
+
+			cts.Color = ColorNever
+			Expect(Render(tree, DefaultVisitor, cts)).To(Equal(buf.String()))
+
+			buf.Reset()
+			cts.Color = ColorAlways
+			Expect(RenderTo(&buf, tree, DefaultVisitor, cts)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("\x1b[1mroot\x1b[0m"))
+		})
+
+		It("lines up styled continuation lines", func() {
+			cts.ChildIndent = 4
+			Expect(cts.indentLine("foo")).To(Equal("\x1b[2m|\x1b[0m   foo"))
+			Expect(cts.indentLineLastNode("foo")).To(Equal("    foo"))
+		})
+
+	})
+
+})
@@ -25,12 +25,27 @@ to do is tag your data structure, such as in:
 	    Children []node   `asciitree:"children"`
 	}
 
+Label fields don't need to be strings: labels implementing fmt.Stringer or
+encoding.TextMarshaler are represented by their texts, while other basic
+kinds, such as integers, are represented in their default formats. A label
+tag can also specify a format, such as “asciitree:"label,format=%08x"”.
+Multiple fields can contribute parts to a label when their label tags specify
+the order of their parts, such as “asciitree:"label,part=1"” and
+“asciitree:"label,part=2,format=(%s)"”. The non-empty label parts are then
+joined, separated by spaces.
+
 Asciitree can both work with a single-root tree, as well as with multiple
 roots. Simply pass the Render() function either a single root node, or a slice
 of root nodes, and it will handle both cases automatically. You can also pass
 a user-data struct with only an “asciitree:"roots"” tag, attached to a struct
 field storing your root nodes. Traversal will then proceed as usual.
 
+Children don't need to be stored in slices: they can also be stored in
+arrays, maps (ordered by their keys), iter.Seq and iter.Seq2 iterators,
+channels, container/list lists, or collections implementing ChildIterator.
+With “asciitree:"children,keylabels"”, the map and iter.Seq2 keys become the
+labels of the child nodes.
+
 In addition to automatically rendering tagged user-data structs, asciitree can
 also automatically traverse maps if those follow these rules: (1) your map
 must be of type “map[string]any”. And (2), your map needs to use the
@@ -39,5 +54,39 @@ these keys is missing, asciitree will assume them to be zero. Finally, you can
 optionally pass in a top-level map with the well-known map key “roots” holding
 your root nodes. Or you can pass in a slide of root nodes. The Render()
 function will detect these use case automatically and handle them accordingly.
+
+Maps of maps where the map keys name the child nodes, such as decoded JSON
+configurations, can be rendered using a KeyedMapVisitor instead. It renders the
+map keys as node labels, and scalar values as “key: value” leaf nodes or,
+optionally, as “key: value” properties, visiting the map entries in order of
+their keys.
+
+Types that cannot be tagged, such as types from other packages, can instead
+implement the TreeNode interface, for instance, using wrapper types. The
+TreeNode interface takes precedence over tagged fields and well-known map
+keys.
+
+Instead of a []string, properties can also be a []Property, with
+sub-properties rendered as sub-trees below their properties, a
+map[string]string, or a slice of fmt.Stringer elements. Additionally,
+individual struct fields of any type can be tagged as properties, such as
+“asciitree:"property,name=uid,omitempty"”, and then get rendered as “uid:
+1000” properties. The omitempty option leaves out fields with zero values,
+while a format option formats the field values.
+
+Key-value attributes of nodes can be stored in a struct field tagged with
+“asciitree:"attributes"”, or in the well-known map key “attributes”, in form of
+either a map with string keys or a []KeyValue. Attributes are rendered after
+the properties of a node as “key: value” properties, with their values lined
+up. Similarly, node annotations, such as sizes or statuses, can be stored in a
+struct field tagged with “asciitree:"annotation"”, or in the well-known map key
+“annotation”. Annotations are rendered right of their node labels, optionally
+lined up in a common column.
+
+Render panics when it comes across unsupported roots or nodes, such as an int
+node. When rendering untrusted user-supplied trees, use TryRender or RenderTo
+instead, which return a *NodeError describing the offending type and the path
+of node labels leading to it. Similarly, TryLines and TryNodeLines render such
+trees lazily line by line, reporting errors instead of panicking.
 */
 package asciitree
@@ -0,0 +1,60 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Errors returned (or panicked with) when trying to render unsupported tree
+// data. Use errors.Is to check for them, and errors.As to retrieve the
+// details in form of a *NodeError.
+var (
+	ErrUnsupportedRootsType = errors.New("unsupported asciitree roots type")
+	ErrUnsupportedNodeType  = errors.New("unsupported asciitree node type")
+	ErrBadPropertiesField   = errors.New("unsupported asciitree properties type")
+	ErrBadAttributesField   = errors.New("unsupported asciitree attributes type")
+)
+
+// NodeError describes an offending type encountered while visiting a tree,
+// together with the path to where the offending type was found.
+type NodeError struct {
+	Err  error        // the kind of error, such as ErrUnsupportedNodeType.
+	Type reflect.Type // the offending type; nil for a nil value.
+	Path []string     // labels of the nodes leading to the offending value.
+}
+
+// newNodeError returns a new *NodeError of the specified kind for the passed
+// offending value.
+func newNodeError(err error, v any) *NodeError {
+	return &NodeError{Err: err, Type: reflect.TypeOf(v)}
+}
+
+// Error returns a textual description of this node error, including the
+// offending type and the path to it.
+func (e *NodeError) Error() string {
+	msg := fmt.Sprintf("%s %v", e.Err, e.Type)
+	if len(e.Path) != 0 {
+		msg += fmt.Sprintf(" at %q", e.Path)
+	}
+	return msg
+}
+
+// Unwrap returns the kind of node error, such as ErrUnsupportedNodeType.
+func (e *NodeError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,70 @@
+package asciitree_test
+
+import (
+	"fmt"
+
+	asciitree "github.com/thediveo/go-asciitree/v2"
+)
+
+func ExampleLines() {
+	// user-defined tree data structure with asciitree-related field tags.
+	type tree struct {
+		Label    string `asciitree:"label"`
+		Children []tree `asciitree:"children"`
+	}
+	// set up a tree of nodes.
+	root := tree{
+		Label: "root",
+		Children: []tree{
+			{Label: "child 1"},
+			{Label: "child 2", Children: []tree{
+				{Label: "grandchild 1"},
+				{Label: "grandchild 2"},
+			}},
+			{Label: "child 3"},
+		},
+	}
+	// print the rendered lines with line numbers, stopping after the first
+	// four lines.
+	no := 0
+	for line := range asciitree.Lines(root, asciitree.DefaultVisitor, asciitree.LineTreeStyler) {
+		no++
+		fmt.Printf("%2d %s\n", no, line)
+		if no == 4 {
+			break
+		}
+	}
+	// Output:
+	//  1 root
+	//  2 ├─ child 1
+	//  3 ├─ child 2
+	//  4 │  ├─ grandchild 1
+}
+
+func ExampleNodeLines() {
+	// user-defined tree data structure with asciitree-related field tags.
+	type tree struct {
+		Label    string `asciitree:"label"`
+		Children []tree `asciitree:"children"`
+	}
+	// set up a tree of nodes.
+	root := tree{
+		Label: "root",
+		Children: []tree{
+			{Label: "child 1", Children: []tree{
+				{Label: "grandchild 1"},
+			}},
+			{Label: "child 2"},
+		},
+	}
+	// print the rendered lines together with the nesting depth of their
+	// nodes.
+	for line, info := range asciitree.NodeLines(root, asciitree.DefaultVisitor, asciitree.LineTreeStyler) {
+		fmt.Printf("%-20s (depth %d)\n", line, info.Depth)
+	}
+	// Output:
+	// root                 (depth 0)
+	// ├─ child 1           (depth 1)
+	// │  └─ grandchild 1   (depth 2)
+	// └─ child 2           (depth 1)
+}
@@ -2,6 +2,7 @@ package asciitree_test
 
 import (
 	"fmt"
+	"os"
 
 	asciitree "github.com/thediveo/go-asciitree/v2"
 )
@@ -38,6 +39,37 @@ func ExampleRender() {
 	// `- child 3
 }
 
+func ExampleRenderTo() {
+	// user-defined tree data structure with asciitree-related field tags.
+	type tree struct {
+		Label    string `asciitree:"label"`
+		Children []tree `asciitree:"children"`
+	}
+	// set up a tree of nodes.
+	root := tree{
+		Label: "root",
+		Children: []tree{
+			{Label: "child 1"},
+			{Label: "child 2", Children: []tree{
+				{Label: "grandchild 1"},
+				{Label: "grandchild 2"},
+			}},
+		},
+	}
+	// render the tree directly to stdout, without building a string first.
+	if err := asciitree.RenderTo(os.Stdout, root,
+		asciitree.DefaultVisitor,
+		asciitree.LineTreeStyler); err != nil {
+		fmt.Println("error:", err)
+	}
+	// Output:
+	// root
+	// ├─ child 1
+	// └─ child 2
+	//    ├─ grandchild 1
+	//    └─ grandchild 2
+}
+
 func ExampleRenderPlain() {
 	// user-defined tree data structure with asciitree-related field tags.
 	type tree struct {
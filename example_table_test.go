@@ -0,0 +1,58 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree_test
+
+import (
+	"fmt"
+	"strconv"
+
+	asciitree "github.com/thediveo/go-asciitree/v2"
+)
+
+func ExampleTreeTable() {
+	type process struct {
+		Name     string     `asciitree:"label"`
+		Children []*process `asciitree:"children"`
+		PID      int
+		State    string
+	}
+	root := &process{Name: "systemd", PID: 1, State: "S", Children: []*process{
+		{Name: "sshd", PID: 812, State: "S", Children: []*process{
+			{Name: "bash", PID: 4711, State: "R"},
+		}},
+		{Name: "cron", PID: 93, State: "S"},
+	}}
+	table := &asciitree.TreeTable{
+		TreeHeader: "COMMAND",
+		Columns: []asciitree.Column{
+			{Header: "PID", Align: asciitree.AlignRight, Value: func(node any) string {
+				return strconv.Itoa(node.(*process).PID)
+			}},
+			{Header: "STATE", Value: func(node any) string {
+				return node.(*process).State
+			}},
+		},
+		Header:    true,
+		Separator: true,
+	}
+	fmt.Print(table.Render(root, asciitree.DefaultVisitor, asciitree.LineTreeStyler))
+	// Output:
+	// COMMAND      PID  STATE
+	// ──────────  ────  ─────
+	// systemd        1  S
+	// ├─ sshd      812  S
+	// │  └─ bash  4711  R
+	// └─ cron       93  S
+}
@@ -17,17 +17,41 @@ package asciitree
 import (
 	"reflect"
 	"slices"
+	"strconv"
+	"strings"
 	"sync"
 )
 
 // structFields caches certain field indices relevant to rendering trees from
-// structs, such as the label field, the properties and children fields, and (if
-// any) the roots field.
+// structs, such as the label field(s), the properties and individual property
+// fields, the attributes, annotation, and children fields, and (if any) the
+// roots field.
 type structFields struct {
-	LabelPath      []int // indices path of label field, or nil.
-	PropertiesPath []int // indices path of properties field, or nil.
-	ChildrenPath   []int // indices path of children field, or nil.
-	RootsPath      []int // indices path of roots field, or nil.
+	LabelFields    []labelField    // label field(s) in order of their label parts, or nil.
+	PropertiesPath []int           // indices path of properties field, or nil.
+	PropertyFields []propertyField // individual property fields, or nil.
+	AttributesPath []int           // indices path of attributes field, or nil.
+	AnnotationPath []int           // indices path of annotation field, or nil.
+	ChildrenPath   []int           // indices path of children field, or nil.
+	KeyLabels      bool            // use map and iter.Seq2 keys as children labels.
+	RootsPath      []int           // indices path of roots field, or nil.
+}
+
+// propertyField describes an individual field rendered as a “name: value”
+// property.
+type propertyField struct {
+	Path      []int  // indices path of property field.
+	Name      string // name of the property.
+	Format    string // optional format of the property value.
+	OmitEmpty bool   // leave out the property if its field has the zero value.
+}
+
+// labelField describes a field contributing to the label, either as the
+// sole label field, or as one of several label parts.
+type labelField struct {
+	Path   []int  // indices path of label field.
+	Format string // optional format of the label field.
+	Part   int    // position of the label part, or 0 for the sole label field.
 }
 
 // structFieldsCache is our program-global cache for quickly looking up the
@@ -54,15 +78,18 @@ func structInfoCache(cache *sync.Map, node reflect.Value) *structFields {
 	// asciitree tags, and if found and valid, then learn the field indices.
 	newsf := &structFields{}
 	findFieldsRecursively(structT, nil, newsf)
+	slices.SortStableFunc(newsf.LabelFields, func(a, b labelField) int {
+		return a.Part - b.Part
+	})
 	sf, _ := cache.LoadOrStore(structT, newsf)
 	return sf.(*structFields)
 }
 
-// findsFieldsRecursively locates fields marked as label, properties, children,
-// and roots fields, recording their indices paths in the referenced
-// structFields value. It recursively descends into anonymous structures fields,
-// in a depth first manner, but it does not descend into any named structure
-// fields.
+// findsFieldsRecursively locates fields marked as label, properties, property,
+// attributes, annotation, children, and roots fields, recording their indices
+// paths in the referenced structFields value. It recursively descends into
+// anonymous structures fields, in a depth first manner, but it does not
+// descend into any named structure fields.
 func findFieldsRecursively(structT reflect.Type, path []int, sf *structFields) {
 	for fieldIdx := range structT.NumField() {
 		field := structT.Field(fieldIdx)
@@ -74,16 +101,51 @@ func findFieldsRecursively(structT reflect.Type, path []int, sf *structFields) {
 			findFieldsRecursively(field.Type, append(path, fieldIdx), sf)
 			continue
 		}
-		if sf.LabelPath == nil && hasAsciitreeTagValue(field, "label") {
-			sf.LabelPath = append(slices.Clone(path), fieldIdx)
+		if value, options, _ := parseAsciitreeTag(field); value == "label" {
+			// there can be only one sole label field, but multiple label
+			// part fields.
+			part, hasPart := options["part"]
+			if !hasPart && slices.ContainsFunc(sf.LabelFields, isSoleLabelField) {
+				continue
+			}
+			partNo, _ := strconv.Atoi(part)
+			sf.LabelFields = append(sf.LabelFields, labelField{
+				Path:   append(slices.Clone(path), fieldIdx),
+				Format: options["format"],
+				Part:   partNo,
+			})
+			continue
+		}
+		if value, options, _ := parseAsciitreeTag(field); value == "property" {
+			name := options["name"]
+			if name == "" {
+				name = field.Name
+			}
+			_, omitEmpty := options["omitempty"]
+			sf.PropertyFields = append(sf.PropertyFields, propertyField{
+				Path:      append(slices.Clone(path), fieldIdx),
+				Name:      name,
+				Format:    options["format"],
+				OmitEmpty: omitEmpty,
+			})
 			continue
 		}
 		if sf.PropertiesPath == nil && hasAsciitreeTagValue(field, "properties") {
 			sf.PropertiesPath = append(slices.Clone(path), fieldIdx)
 			continue
 		}
+		if sf.AttributesPath == nil && hasAsciitreeTagValue(field, "attributes") {
+			sf.AttributesPath = append(slices.Clone(path), fieldIdx)
+			continue
+		}
+		if sf.AnnotationPath == nil && hasAsciitreeTagValue(field, "annotation") {
+			sf.AnnotationPath = append(slices.Clone(path), fieldIdx)
+			continue
+		}
 		if sf.ChildrenPath == nil && hasAsciitreeTagValue(field, "children") {
 			sf.ChildrenPath = append(slices.Clone(path), fieldIdx)
+			_, options, _ := parseAsciitreeTag(field)
+			_, sf.KeyLabels = options["keylabels"]
 			continue
 		}
 		if sf.RootsPath == nil && hasAsciitreeTagValue(field, "roots") {
@@ -93,9 +155,35 @@ func findFieldsRecursively(structT reflect.Type, path []int, sf *structFields) {
 	}
 }
 
+// isSoleLabelField returns true if the passed label field isn't a label part.
+func isSoleLabelField(lf labelField) bool {
+	return lf.Part == 0
+}
+
 // hasAsciitreeTagValue returns true, if the passed field has the "asciitree" tag
-// with the specified value; otherwise false.
+// with the specified value, ignoring any options; otherwise false.
 func hasAsciitreeTagValue(field reflect.StructField, value string) bool {
-	v, ok := field.Tag.Lookup("asciitree")
+	v, _, ok := parseAsciitreeTag(field)
 	return ok && v == value
 }
+
+// parseAsciitreeTag returns the value of the "asciitree" tag of the passed
+// field, as well as its options in form of “name=value” pairs following the
+// tag value, separated by commas, such as in `asciitree:"label,format=%x"`.
+// It returns false if the field has no "asciitree" tag.
+func parseAsciitreeTag(field reflect.StructField) (value string, options map[string]string, ok bool) {
+	tag, ok := field.Tag.Lookup("asciitree")
+	if !ok {
+		return "", nil, false
+	}
+	value, opts, _ := strings.Cut(tag, ",")
+	if opts == "" {
+		return value, nil, true
+	}
+	options = map[string]string{}
+	for _, opt := range strings.Split(opts, ",") {
+		name, optValue, _ := strings.Cut(opt, "=")
+		options[strings.TrimSpace(name)] = optValue
+	}
+	return value, options, true
+}
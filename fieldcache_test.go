@@ -34,6 +34,20 @@ var _ = Describe("field index cache", func() {
 		Entry(nil, `foo:"bar"`, "", false),
 		Entry(nil, `foo:"bar" asciitree:"label"`, "label", true),
 		Entry(nil, `foo:"bar" asciitree:"foo"`, "label", false),
+		Entry(nil, `asciitree:"label,format=%08x"`, "label", true),
+	)
+
+	DescribeTable("parsing asciitree tag options",
+		func(tag string, expectedValue string, expectedOptions map[string]string) {
+			value, options, ok := parseAsciitreeTag(reflect.StructField{Tag: reflect.StructTag(tag)})
+			Expect(ok).To(Equal(tag != ""))
+			Expect(value).To(Equal(expectedValue))
+			Expect(options).To(Equal(expectedOptions))
+		},
+		Entry(nil, ``, "", nil),
+		Entry(nil, `asciitree:"label"`, "label", nil),
+		Entry(nil, `asciitree:"label,format=%08x"`, "label", map[string]string{"format": "%08x"}),
+		Entry(nil, `asciitree:"label,foo,format=%d"`, "label", map[string]string{"foo": "", "format": "%d"}),
 	)
 
 	When("looking up types in the cache", func() {
@@ -54,8 +68,10 @@ var _ = Describe("field index cache", func() {
 			}
 			si := structInfoCache(cache, reflect.ValueOf(T{foo: 42}))
 			Expect(si).To(And(
-				HaveField("LabelPath", BeNil()),
+				HaveField("LabelFields", BeNil()),
 				HaveField("PropertiesPath", BeNil()),
+				HaveField("AttributesPath", BeNil()),
+				HaveField("AnnotationPath", BeNil()),
 				HaveField("ChildrenPath", BeNil()),
 				HaveField("RootsPath", BeNil())))
 			siAgain := structInfoCache(cache, reflect.ValueOf(T{}))
@@ -70,16 +86,48 @@ var _ = Describe("field index cache", func() {
 				Bar int
 				Baz []string `asciitree:"properties"`
 				T
-				Coolz []U `asciitree:"children"`
-				Ruhtz []T `asciitree:"roots"`
+				Coolz []U            `asciitree:"children"`
+				Ruhtz []T            `asciitree:"roots"`
+				Attrz map[string]any `asciitree:"attributes"`
+				Annot string         `asciitree:"annotation"`
 			}
 			Expect(structInfoCache(cache, reflect.ValueOf(U{}))).To(And(
-				HaveField("LabelPath", HaveExactElements(2, 0)),
+				HaveField("LabelFields", HaveExactElements(
+					labelField{Path: []int{2, 0}})),
 				HaveField("PropertiesPath", HaveExactElements(1)),
+				HaveField("AttributesPath", HaveExactElements(5)),
+				HaveField("AnnotationPath", HaveExactElements(6)),
 				HaveField("ChildrenPath", HaveExactElements(3)),
 				HaveField("RootsPath", HaveExactElements(4))))
 		})
 
+		It("finds embedded label fields with format options", func() {
+			type T struct {
+				Foo string `asciitree:"label,format=%q"`
+			}
+			type U struct {
+				Bar int
+				T
+			}
+			Expect(structInfoCache(cache, reflect.ValueOf(U{}))).To(
+				HaveField("LabelFields", HaveExactElements(
+					labelField{Path: []int{1, 0}, Format: "%q"})))
+		})
+
+		It("finds label parts", func() {
+			type T struct {
+				Kind  string `asciitree:"label,part=2,format=(%s)"`
+				Name  string `asciitree:"label,part=1"`
+				Label string `asciitree:"label"`
+				Other string `asciitree:"label"`
+			}
+			Expect(structInfoCache(cache, reflect.ValueOf(T{}))).To(
+				HaveField("LabelFields", HaveExactElements(
+					labelField{Path: []int{2}},
+					labelField{Path: []int{1}, Part: 1},
+					labelField{Path: []int{0}, Format: "(%s)", Part: 2})))
+		})
+
 	})
 
 })
@@ -0,0 +1,141 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+// FuncVisitor visits tree nodes of type T using typed accessor functions,
+// without any reflection. This avoids implementing a Visitor with type
+// assertions on any values and gives large homogeneous trees a fast path.
+//
+// The roots passed to the Render...() functions must be either a []T, a []any
+// with elements of type T, or a single root node of type T. Any of the
+// accessor functions can be nil, in which case the nodes have no labels,
+// properties, or children respectively.
+type FuncVisitor[T any] struct {
+	LabelFunc      func(node T) string
+	PropertiesFunc func(node T) []string
+	ChildrenFunc   func(node T) []T
+}
+
+// NewFuncVisitor returns a new visitor for tree nodes of type T that uses the
+// passed accessor functions to retrieve the labels, properties, and children
+// of nodes.
+func NewFuncVisitor[T any](
+	label func(node T) string,
+	properties func(node T) []string,
+	children func(node T) []T,
+) *FuncVisitor[T] {
+	return &FuncVisitor[T]{
+		LabelFunc:      label,
+		PropertiesFunc: properties,
+		ChildrenFunc:   children,
+	}
+}
+
+var _ ErrorVisitor = (*FuncVisitor[any])(nil)
+
+// Roots returns the list of root nodes. Roots panics when the roots are of an
+// unsupported type.
+func (v *FuncVisitor[T]) Roots(roots any) []any {
+	nodes, err := v.TryRoots(roots)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// TryRoots works like Roots, but returns an error instead of panicking when
+// the roots are of an unsupported type.
+func (v *FuncVisitor[T]) TryRoots(roots any) ([]any, error) {
+	if roots, ok := roots.([]T); ok {
+		return anys(roots), nil
+	}
+	if roots, ok := roots.([]any); ok {
+		for _, root := range roots {
+			if _, ok := root.(T); !ok {
+				return nil, newNodeError(ErrUnsupportedNodeType, root)
+			}
+		}
+		return roots, nil
+	}
+	if root, ok := roots.(T); ok {
+		return []any{root}, nil
+	}
+	return nil, newNodeError(ErrUnsupportedRootsType, roots)
+}
+
+// Label returns the label of a tree node. Label panics when the node is not
+// of type T.
+func (v *FuncVisitor[T]) Label(node any) string {
+	label, err := v.TryLabel(node)
+	if err != nil {
+		panic(err)
+	}
+	return label
+}
+
+// TryLabel works like Label, but returns an error instead of panicking when
+// the node is not of type T.
+func (v *FuncVisitor[T]) TryLabel(node any) (string, error) {
+	n, ok := node.(T)
+	if !ok {
+		return "", newNodeError(ErrUnsupportedNodeType, node)
+	}
+	if v.LabelFunc == nil {
+		return "", nil
+	}
+	return v.LabelFunc(n), nil
+}
+
+// Get returns the label, properties, and children of a tree node. Get panics
+// when the node is not of type T.
+func (v *FuncVisitor[T]) Get(node any) (label string, properties []string, children []any) {
+	label, properties, children, err := v.TryGet(node)
+	if err != nil {
+		panic(err)
+	}
+	return label, properties, children
+}
+
+// TryGet works like Get, but returns an error instead of panicking when the
+// node is not of type T.
+func (v *FuncVisitor[T]) TryGet(node any) (label string, properties []string, children []any, err error) {
+	n, ok := node.(T)
+	if !ok {
+		return "", nil, nil, newNodeError(ErrUnsupportedNodeType, node)
+	}
+	if v.LabelFunc != nil {
+		label = v.LabelFunc(n)
+	}
+	if v.PropertiesFunc != nil {
+		properties = v.PropertiesFunc(n)
+	}
+	if v.ChildrenFunc != nil {
+		children = anys(v.ChildrenFunc(n))
+	}
+	return label, properties, children, nil
+}
+
+// anys returns the passed typed nodes as a slice of any nodes, or nil for no
+// nodes.
+func anys[T any](nodes []T) []any {
+	if len(nodes) == 0 {
+		return nil
+	}
+	anynodes := make([]any, len(nodes))
+	for idx, node := range nodes {
+		anynodes[idx] = node
+	}
+	return anynodes
+}
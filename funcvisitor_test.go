@@ -0,0 +1,108 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("func visitor", func() {
+
+	type task struct {
+		name     string
+		deps     []string
+		subtasks []*task
+	}
+
+	v := NewFuncVisitor(
+		func(t *task) string { return t.name },
+		func(t *task) []string { return t.deps },
+		func(t *task) []*task { return t.subtasks })
+
+	build := &task{name: "build", deps: []string{"go"}, subtasks: []*task{
+		{name: "compile"},
+		{name: "link", deps: []string{"ld"}},
+	}}
+	test := &task{name: "test"}
+
+	It("gets labels, properties, and children", func() {
+		Expect(v.Label(build)).To(Equal("build"))
+		label, props, children := v.Get(build)
+		Expect(label).To(Equal("build"))
+		Expect(props).To(HaveExactElements("go"))
+		Expect(children).To(HaveExactElements(build.subtasks[0], build.subtasks[1]))
+
+		_, _, children = v.Get(test)
+		Expect(children).To(BeNil())
+	})
+
+	It("handles missing accessor functions", func() {
+		v := &FuncVisitor[*task]{}
+		label, props, children := v.Get(build)
+		Expect(label).To(BeEmpty())
+		Expect(props).To(BeNil())
+		Expect(children).To(BeNil())
+		Expect(v.Label(build)).To(BeEmpty())
+	})
+
+	It("gets roots", func() {
+		Expect(v.Roots(build)).To(HaveExactElements(build))
+		Expect(v.Roots([]*task{build, test})).To(HaveExactElements(build, test))
+		Expect(v.Roots([]any{test, build})).To(HaveExactElements(test, build))
+	})
+
+	It("reports unsupported roots and nodes", func() {
+		_, err := v.TryRoots(42)
+		Expect(err).To(MatchError(ErrUnsupportedRootsType))
+		_, err = v.TryRoots([]any{build, 42})
+		Expect(err).To(MatchError(ErrUnsupportedNodeType))
+		_, err = v.TryLabel("foo")
+		Expect(err).To(MatchError(ErrUnsupportedNodeType))
+		_, _, _, err = v.TryGet(task{})
+		Expect(err).To(MatchError(ErrUnsupportedNodeType))
+
+		Expect(func() { v.Roots(42) }).To(PanicWith(MatchError(ErrUnsupportedRootsType)))
+		Expect(func() { v.Label(42) }).To(PanicWith(MatchError(ErrUnsupportedNodeType)))
+		Expect(func() { v.Get(42) }).To(PanicWith(MatchError(ErrUnsupportedNodeType)))
+	})
+
+	It("renders trees", func() {
+		Expect(Render([]*task{build, test}, v, DefaultTreeStyler)).To(Equal(`build
+|  * go
++- compile
+` + "`" + `- link
+      * ld
+test
+`))
+	})
+
+	It("renders trees of non-struct nodes", func() {
+		tree := map[string][]string{
+			"/":    {"/etc", "/tmp"},
+			"/etc": {"/etc/hosts"},
+		}
+		v := NewFuncVisitor(
+			func(path string) string { return path },
+			nil,
+			func(path string) []string { return tree[path] })
+		Expect(Render("/", v, DefaultTreeStyler)).To(Equal(`/
++- /etc
+|  ` + "`" + `- /etc/hosts
+` + "`" + `- /tmp
+`))
+	})
+
+})
@@ -0,0 +1,172 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// KeyedMapVisitor visits trees of nested maps (and slices), such as decoded
+// JSON or YAML configurations, where the map keys name the child nodes and the
+// map values are the subtrees, instead of using the well-known “label” and
+// “children” keys. Map entries are visited in order of their keys, while
+// slice elements are keyed by their indices.
+//
+// Map entries and slice elements with scalar values are rendered as “key:
+// value” leaf nodes or, if ScalarProperties is set, as “key: value”
+// properties of their parent nodes.
+//
+// The nodes visited are KeyedNode values, with the top-level map entries (or
+// slice elements) passed as roots becoming the root nodes.
+type KeyedMapVisitor struct {
+	ScalarProperties bool // render scalar values as properties instead of leaf nodes.
+}
+
+var _ ErrorVisitor = (*KeyedMapVisitor)(nil)
+
+// NewKeyedMapVisitor returns a new visitor for trees of nested maps keyed by
+// the labels of their child nodes, optionally rendering scalar values as
+// properties.
+func NewKeyedMapVisitor(scalarProperties bool) *KeyedMapVisitor {
+	return &KeyedMapVisitor{ScalarProperties: scalarProperties}
+}
+
+// Roots returns the entries of the passed map (or the elements of a slice)
+// as the root nodes. Alternatively, roots can be a single KeyedNode. Roots
+// panics when the roots are of an unsupported type.
+func (v *KeyedMapVisitor) Roots(roots any) []any {
+	nodes, err := v.TryRoots(roots)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// TryRoots works like Roots, but returns an error instead of panicking when
+// the roots are of an unsupported type.
+func (v *KeyedMapVisitor) TryRoots(roots any) ([]any, error) {
+	if kn, ok := roots.(KeyedNode); ok {
+		return []any{kn}, nil
+	}
+	rv, ok := container(reflect.ValueOf(roots))
+	if !ok {
+		return nil, newNodeError(ErrUnsupportedRootsType, roots)
+	}
+	return entries(rv), nil
+}
+
+// Label returns the label of a tree node, which is either its key, or “key:
+// value” for a scalar value. Label panics when the node isn't a KeyedNode.
+func (v *KeyedMapVisitor) Label(node any) string {
+	label, err := v.TryLabel(node)
+	if err != nil {
+		panic(err)
+	}
+	return label
+}
+
+// TryLabel works like Label, but returns an error instead of panicking when
+// the node isn't a KeyedNode.
+func (v *KeyedMapVisitor) TryLabel(node any) (string, error) {
+	kn, ok := node.(KeyedNode)
+	if !ok {
+		return "", newNodeError(ErrUnsupportedNodeType, node)
+	}
+	if _, ok := container(reflect.ValueOf(kn.Node)); ok {
+		return kn.Key, nil
+	}
+	return scalarText(kn), nil
+}
+
+// Get returns the label, properties, and children of a tree node. Get panics
+// when the node isn't a KeyedNode.
+func (v *KeyedMapVisitor) Get(node any) (label string, properties []string, children []any) {
+	label, properties, children, err := v.TryGet(node)
+	if err != nil {
+		panic(err)
+	}
+	return label, properties, children
+}
+
+// TryGet works like Get, but returns an error instead of panicking when the
+// node isn't a KeyedNode.
+func (v *KeyedMapVisitor) TryGet(node any) (label string, properties []string, children []any, err error) {
+	kn, ok := node.(KeyedNode)
+	if !ok {
+		return "", nil, nil, newNodeError(ErrUnsupportedNodeType, node)
+	}
+	nodeV, ok := container(reflect.ValueOf(kn.Node))
+	if !ok {
+		return scalarText(kn), nil, nil, nil
+	}
+	children = entries(nodeV)
+	if !v.ScalarProperties {
+		return kn.Key, nil, children, nil
+	}
+	children = slices.DeleteFunc(children, func(child any) bool {
+		kn := child.(KeyedNode)
+		if _, ok := container(reflect.ValueOf(kn.Node)); ok {
+			return false
+		}
+		properties = append(properties, scalarText(kn))
+		return true
+	})
+	return kn.Key, properties, children, nil
+}
+
+// container returns the passed value with any interfaces and pointers
+// removed and true if it is a map, slice, or array; otherwise, it returns
+// false.
+func container(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return v, true
+	}
+	return v, false
+}
+
+// entries returns the entries of the passed map in order of their keys, or
+// the elements of the passed slice or array keyed by their indices, as
+// KeyedNodes.
+func entries(v reflect.Value) []any {
+	if v.Kind() != reflect.Map {
+		nodes := make([]any, v.Len())
+		for idx := range nodes {
+			nodes[idx] = KeyedNode{Key: strconv.Itoa(idx), Node: v.Index(idx).Interface()}
+		}
+		return nodes
+	}
+	keys := v.MapKeys()
+	slices.SortFunc(keys, compareKeys)
+	nodes := make([]any, len(keys))
+	for idx, key := range keys {
+		nodes[idx] = KeyedNode{Key: formatLabel(key, ""), Node: v.MapIndex(key).Interface()}
+	}
+	return nodes
+}
+
+// scalarText returns the “key: value” text of the passed keyed node with a
+// scalar value.
+func scalarText(kn KeyedNode) string {
+	if kn.Node == nil {
+		return kn.Key + ": null"
+	}
+	return kn.Key + ": " + formatLabel(reflect.ValueOf(kn.Node), "")
+}
@@ -0,0 +1,124 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("keyed map visitor", func() {
+
+	config := map[string]any{
+		"etc": map[string]any{
+			"nginx": map[string]any{
+				"workers": 4,
+				"listen":  []any{80, 443},
+				"user":    "www-data",
+			},
+			"hostname": "box",
+		},
+		"debug": nil,
+	}
+
+	It("returns roots", func() {
+		v := NewKeyedMapVisitor(false)
+		Expect(v.TryRoots(config)).To(HaveExactElements(
+			KeyedNode{Key: "debug", Node: nil},
+			KeyedNode{Key: "etc", Node: config["etc"]},
+		))
+		Expect(v.TryRoots(&[]string{"a"})).To(HaveExactElements(
+			KeyedNode{Key: "0", Node: "a"}))
+		kn := KeyedNode{Key: "root", Node: config}
+		Expect(v.TryRoots(kn)).To(HaveExactElements(kn))
+
+		_, err := v.TryRoots(42)
+		Expect(err).To(MatchError(ErrUnsupportedRootsType))
+		Expect(func() { v.Roots(42) }).To(PanicWith(MatchError(ErrUnsupportedRootsType)))
+	})
+
+	It("returns labels", func() {
+		v := NewKeyedMapVisitor(false)
+		Expect(v.Label(KeyedNode{Key: "etc", Node: map[string]any{}})).To(Equal("etc"))
+		Expect(v.Label(KeyedNode{Key: "port", Node: 80})).To(Equal("port: 80"))
+		Expect(v.Label(KeyedNode{Key: "debug"})).To(Equal("debug: null"))
+
+		_, err := v.TryLabel("etc")
+		Expect(err).To(MatchError(ErrUnsupportedNodeType))
+		Expect(func() { v.Label("etc") }).To(PanicWith(MatchError(ErrUnsupportedNodeType)))
+	})
+
+	It("gets node details", func() {
+		node := KeyedNode{Key: "nginx", Node: map[string]any{
+			"user":   "www-data",
+			"listen": []int{80},
+		}}
+		label, props, children := NewKeyedMapVisitor(false).Get(node)
+		Expect(label).To(Equal("nginx"))
+		Expect(props).To(BeEmpty())
+		Expect(children).To(HaveExactElements(
+			KeyedNode{Key: "listen", Node: []int{80}},
+			KeyedNode{Key: "user", Node: "www-data"},
+		))
+
+		label, props, children = NewKeyedMapVisitor(true).Get(node)
+		Expect(label).To(Equal("nginx"))
+		Expect(props).To(HaveExactElements("user: www-data"))
+		Expect(children).To(HaveExactElements(
+			KeyedNode{Key: "listen", Node: []int{80}},
+		))
+
+		_, _, _, err := NewKeyedMapVisitor(false).TryGet(42)
+		Expect(err).To(MatchError(ErrUnsupportedNodeType))
+		Expect(func() { NewKeyedMapVisitor(false).Get(42) }).To(
+			PanicWith(MatchError(ErrUnsupportedNodeType)))
+	})
+
+	It("renders scalars as leaf nodes", func() {
+		Expect(Render(config, NewKeyedMapVisitor(false), DefaultTreeStyler)).To(Equal(`debug: null
+etc
++- hostname: box
+` + "`" + `- nginx
+   +- listen
+   |  +- 0: 80
+   |  ` + "`" + `- 1: 443
+   +- user: www-data
+   ` + "`" + `- workers: 4
+`))
+	})
+
+	It("renders scalars as properties", func() {
+		Expect(Render(config, NewKeyedMapVisitor(true), DefaultTreeStyler)).To(Equal(`debug: null
+etc
+|  * hostname: box
+` + "`" + `- nginx
+   |  * user: www-data
+   |  * workers: 4
+   ` + "`" + `- listen
+         * 0: 80
+         * 1: 443
+`))
+	})
+
+	It("detects cycles", func() {
+		tree := map[string]any{}
+		tree["self"] = tree
+		Expect(Render(KeyedNode{Key: "root", Node: tree},
+			NewKeyedMapVisitor(false), DefaultTreeStyler)).To(Equal(`root
+` + "`" + `- ↻ (cycle to root)
+`))
+	})
+
+})
@@ -0,0 +1,128 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// structLabel returns the label of the passed struct node, taken from the
+// label field (if any) and formatted as necessary. When there are multiple
+// label part fields, structLabel joins their non-empty texts in order of
+// their parts, separated by spaces.
+func structLabel(node reflect.Value, si *structFields) string {
+	switch len(si.LabelFields) {
+	case 0:
+		return ""
+	case 1:
+		lf := si.LabelFields[0]
+		return formatLabel(node.FieldByIndex(lf.Path), lf.Format)
+	}
+	parts := make([]string, 0, len(si.LabelFields))
+	for _, lf := range si.LabelFields {
+		if part := formatLabel(node.FieldByIndex(lf.Path), lf.Format); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// mapLabel returns the label of the passed map node, taken from the
+// well-known “label” key and formatted as necessary.
+func mapLabel(node reflect.Value) string {
+	return formatLabel(mapValue(node, "label"), "")
+}
+
+// formatLabel returns the textual representation of the passed label value
+// (unpacking an interface value where necessary). Given a format, the label
+// value is formatted using fmt.Sprintf. Otherwise, label values implementing
+// fmt.Stringer or encoding.TextMarshaler (including on pointer receivers of
+// addressable values) are represented by their texts, while pointers are
+// followed, and basic kinds formatted in their default formats. It returns an
+// empty label for a nil value.
+func formatLabel(v reflect.Value, format string) string {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return ""
+	}
+	if v.CanInterface() && format == "" {
+		if text, ok := textOf(v); ok {
+			return text
+		}
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		return formatLabel(v.Elem(), format)
+	}
+	if v.CanInterface() && format != "" {
+		return fmt.Sprintf(format, v.Interface())
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.Complex64:
+		return strconv.FormatComplex(v.Complex(), 'g', -1, 64)
+	case reflect.Complex128:
+		return strconv.FormatComplex(v.Complex(), 'g', -1, 128)
+	}
+	if v.CanInterface() {
+		return fmt.Sprint(v.Interface())
+	}
+	return ""
+}
+
+// textOf returns the text of the passed value if it implements fmt.Stringer
+// or encoding.TextMarshaler, either directly or on a pointer receiver if the
+// value is addressable. Stringers take precedence over text marshalers.
+func textOf(v reflect.Value) (string, bool) {
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return "", false
+	}
+	candidates := []any{v.Interface()}
+	if v.CanAddr() {
+		candidates = append(candidates, v.Addr().Interface())
+	}
+	for _, candidate := range candidates {
+		if stringer, ok := candidate.(fmt.Stringer); ok {
+			return stringer.String(), true
+		}
+	}
+	for _, candidate := range candidates {
+		if marshaler, ok := candidate.(encoding.TextMarshaler); ok {
+			if text, err := marshaler.MarshalText(); err == nil {
+				return string(text), true
+			}
+		}
+	}
+	return "", false
+}
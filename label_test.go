@@ -0,0 +1,130 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"errors"
+	"net"
+	"reflect"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type color int
+
+func (c color) String() string { return [...]string{"red", "green"}[c] }
+
+type ptrStringer struct{ name string }
+
+func (p *ptrStringer) String() string { return "*" + p.name }
+
+type textual struct{}
+
+func (textual) MarshalText() ([]byte, error) { return []byte("text"), nil }
+
+type badTextual struct{}
+
+func (badTextual) MarshalText() ([]byte, error) { return nil, errors.New("nope") }
+
+var _ = Describe("labels", func() {
+
+	DescribeTable("formatting label values",
+		func(label any, format string, expected string) {
+			Expect(formatLabel(reflect.ValueOf(label), format)).To(Equal(expected))
+		},
+		Entry(nil, nil, "", ""),
+		Entry(nil, "foo", "", "foo"),
+		Entry(nil, 42, "", "42"),
+		Entry(nil, int8(-42), "", "-42"),
+		Entry(nil, uint16(42), "", "42"),
+		Entry(nil, 1.5, "", "1.5"),
+		Entry(nil, float32(0.1), "", "0.1"),
+		Entry(nil, complex(1, 2), "", "(1+2i)"),
+		Entry(nil, true, "", "true"),
+		Entry(nil, color(1), "", "green"),
+		Entry(nil, &ptrStringer{name: "foo"}, "", "*foo"),
+		Entry(nil, textual{}, "", "text"),
+		Entry(nil, badTextual{}, "", "{}"),
+		Entry(nil, net.IPv4(127, 0, 0, 1), "", "127.0.0.1"),
+		Entry(nil, (*int)(nil), "", ""),
+		Entry(nil, new(int), "", "0"),
+		Entry(nil, 42, "%08x", "0000002a"),
+		Entry(nil, new(int), "%03d", "000"),
+		Entry(nil, color(0), "[%s]", "[red]"),
+	)
+
+	It("uses pointer receivers of addressable label fields", func() {
+		type N struct {
+			Name ptrStringer `asciitree:"label"`
+		}
+		Expect(DefaultVisitor.Label(&N{Name: ptrStringer{name: "foo"}})).To(Equal("*foo"))
+		Expect(DefaultVisitor.Label(N{Name: ptrStringer{name: "foo"}})).To(Equal("{foo}"))
+	})
+
+	It("formats unexported label fields of basic kinds", func() {
+		type N struct {
+			id uint `asciitree:"label"`
+		}
+		Expect(DefaultVisitor.Label(N{id: 42})).To(Equal("42"))
+	})
+
+	It("composes labels from multiple label parts", func() {
+		type Meta struct {
+			Kind string `asciitree:"label,part=2,format=(%s)"`
+		}
+		type Pod struct {
+			Meta
+			Name     string `asciitree:"label,part=1"`
+			Children []Pod  `asciitree:"children"`
+		}
+		Expect(DefaultVisitor.Label(Pod{Name: "web-1", Meta: Meta{Kind: "Pod"}})).To(
+			Equal("web-1 (Pod)"))
+		Expect(Render(Pod{Name: "web", Meta: Meta{Kind: "Deployment"}, Children: []Pod{
+			{Name: "web-1", Meta: Meta{Kind: "Pod"}},
+			{Name: "web-2"},
+		}}, NewMapStructVisitor(true, false), DefaultTreeStyler)).To(Equal(`web (Deployment)
++- web-1 (Pod)
+` + "`" + `- web-2 ()
+`))
+	})
+
+	It("skips empty label parts", func() {
+		type N struct {
+			Name  string `asciitree:"label,part=1"`
+			Extra string `asciitree:"label,part=2"`
+		}
+		Expect(DefaultVisitor.Label(N{Name: "foo"})).To(Equal("foo"))
+		Expect(DefaultVisitor.Label(N{Extra: "bar"})).To(Equal("bar"))
+	})
+
+	It("renders non-string labels", func() {
+		type N struct {
+			ID       int   `asciitree:"label,format=%08x"`
+			Children []any `asciitree:"children"`
+		}
+		type IPNode struct {
+			IP net.IP `asciitree:"label"`
+		}
+		Expect(Render(N{ID: 42, Children: []any{
+			IPNode{IP: net.IPv4(10, 0, 0, 1)},
+			map[string]any{"label": color(0)},
+		}}, DefaultVisitor, DefaultTreeStyler)).To(Equal(`0000002a
++- 10.0.0.1
+` + "`" + `- red
+`))
+	})
+
+})
@@ -0,0 +1,137 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"iter"
+)
+
+// LineKind tells what a particular rendered line depicts, such as a node label
+// or a node property.
+type LineKind int
+
+// The kinds of rendered lines.
+const (
+	LabelLine          LineKind = iota // line with the label of a node.
+	PropertyLine                       // line with a property of a node.
+	CycleLine                          // line with a cycle back to an ancestor node.
+	SharedLine                         // line with a reference to a shared node rendered above.
+	ElisionLine                        // line telling about descendants left out below a node.
+	MoreChildrenLine                   // line telling about children left out.
+	MorePropertiesLine                 // line telling about properties left out.
+)
+
+// LineInfo describes the node a rendered line belongs to.
+type LineInfo struct {
+	Node  any      // the node this line belongs to.
+	Depth int      // nesting depth of the node, with root nodes at depth 0.
+	Kind  LineKind // kind of line, such as a label or property line.
+
+	Continuation bool // line continues a multi-line label or property.
+
+	annotation string // annotation of the node still to be rendered right of its label line.
+}
+
+// Lines returns an iterator producing the rendered lines of a tree (or
+// multi-root tree) one by one, using the supplied visitor and tree styler. The
+// individual lines don't contain any trailing line breaks.
+//
+// In contrast to Render, Lines renders lazily, so callers can paginate,
+// filter, or prefix lines, or stop early without having to render the whole
+// tree first.
+//
+// The roots are resolved when calling Lines, while the tree nodes are visited
+// only as the iteration proceeds. Each iteration renders the tree afresh, so
+// the returned iterator can be used multiple times. Lines panics when
+// encountering unsupported roots, and the returned iterator panics when
+// encountering unsupported nodes; use TryLines instead to get an error in
+// these situations. Like Render, the returned iterator ignores properties and
+// attributes of map nodes of an unsupported type.
+func Lines(roots any, visitor Visitor, styler *TreeStyler) iter.Seq[string] {
+	return lineTexts(NodeLines(roots, visitor, styler))
+}
+
+// TryLines works like Lines, but instead of panicking it stops the iteration
+// when encountering unsupported roots or nodes. The returned error function
+// then reports the error that stopped the most recent iteration, or nil if
+// the iteration didn't stop due to an error. Unsupported roots are reported
+// right away, even before iterating.
+func TryLines(roots any, visitor Visitor, styler *TreeStyler) (iter.Seq[string], func() error) {
+	lines, errf := nodeLines(roots, visitor, styler, false)
+	return lineTexts(lines), errf
+}
+
+// NodeLines works like Lines, but additionally produces information about the
+// node each rendered line belongs to, such as its nesting depth and the node
+// value itself.
+func NodeLines(roots any, visitor Visitor, styler *TreeStyler) iter.Seq2[string, LineInfo] {
+	lines, errf := nodeLines(roots, visitor, styler, true)
+	if err := errf(); err != nil {
+		panic(err)
+	}
+	return func(yield func(string, LineInfo) bool) {
+		for line, info := range lines {
+			if !yield(line, info) {
+				return
+			}
+		}
+		if err := errf(); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// TryNodeLines works like NodeLines, but reports unsupported roots or nodes
+// using the returned error function in the same way as TryLines does.
+func TryNodeLines(roots any, visitor Visitor, styler *TreeStyler) (iter.Seq2[string, LineInfo], func() error) {
+	return nodeLines(roots, visitor, styler, false)
+}
+
+// nodeLines returns an iterator producing the rendered lines together with
+// their line information, as well as a function returning the error that
+// stopped the most recent iteration, optionally tolerating bad user data as
+// the panicking API always did.
+func nodeLines(roots any, visitor Visitor, styler *TreeStyler, lenient bool) (iter.Seq2[string, LineInfo], func() error) {
+	styler = styler.plain(nil)
+	nodes, err := newRenderer(visitor, styler).roots(roots)
+	if err != nil {
+		return func(func(string, LineInfo) bool) {}, func() error { return err }
+	}
+	return func(yield func(string, LineInfo) bool) {
+		// use a fresh renderer for each iteration, so that cycle and shared
+		// node tracking, as well as errors, don't carry over.
+		r := newRenderer(visitor, styler)
+		r.lenient = lenient
+		err = nil
+		for line, info := range r.trees(nodes) {
+			if !yield(line, info) {
+				return
+			}
+		}
+		err = r.err
+	}, func() error { return err }
+}
+
+// lineTexts returns an iterator producing only the rendered lines of the
+// passed iterator, without their line information.
+func lineTexts(lines iter.Seq2[string, LineInfo]) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for line := range lines {
+			if !yield(line) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"slices"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("line iterators", func() {
+
+	type T struct {
+		Label    string   `asciitree:"label"`
+		Props    []string `asciitree:"properties"`
+		Children []T      `asciitree:"children"`
+	}
+
+	tree := T{
+		Label: "root",
+		Props: []string{"prop"},
+		Children: []T{
+			{Label: "1"},
+			{Label: "2", Children: []T{
+				{Label: "2.1"},
+			}},
+		},
+	}
+
+	It("produces rendered lines", func() {
+		Expect(slices.Collect(Lines(tree, DefaultVisitor, DefaultTreeStyler))).To(
+			HaveExactElements(
+				"root",
+				"|  * prop",
+				"+- 1",
+				"`- 2",
+				"   `- 2.1",
+			))
+	})
+
+	It("stops early", func() {
+		var lines []string
+		for line := range Lines([]T{tree, tree}, DefaultVisitor, DefaultTreeStyler) {
+			lines = append(lines, line)
+			if len(lines) == 3 {
+				break
+			}
+		}
+		Expect(lines).To(HaveExactElements("root", "|  * prop", "+- 1"))
+	})
+
+	It("renders afresh on each iteration", func() {
+		shared := &T{Label: "shared"}
+		type P struct {
+			Label    string `asciitree:"label"`
+			Children []*T   `asciitree:"children"`
+		}
+		root := &P{Label: "root", Children: []*T{shared, shared}}
+		styler := NewTreeStyler(ASCIIStyle)
+		styler.RenderSharedOnce = true
+		lines := Lines(root, DefaultVisitor, styler)
+		expected := []string{"root", "+- shared", "`- shared (see above)"}
+		Expect(slices.Collect(lines)).To(HaveExactElements(expected))
+		Expect(slices.Collect(lines)).To(HaveExactElements(expected))
+
+		lines = Lines([]any{tree, 42}, DefaultVisitor, DefaultTreeStyler)
+		for range 2 {
+			Expect(func() {
+				for range lines {
+				}
+			}).To(PanicWith(MatchError(ErrUnsupportedNodeType)))
+		}
+	})
+
+	It("panics on unsupported roots and nodes", func() {
+		Expect(func() { _ = Lines(42, DefaultVisitor, DefaultTreeStyler) }).To(
+			PanicWith(MatchError(ErrUnsupportedRootsType)))
+		lines := Lines([]any{tree, 42}, DefaultVisitor, DefaultTreeStyler)
+		Expect(func() {
+			for range lines {
+			}
+		}).To(PanicWith(MatchError(ErrUnsupportedNodeType)))
+	})
+
+	It("reports unsupported roots and nodes instead of panicking", func() {
+		lines, errf := TryLines(42, DefaultVisitor, DefaultTreeStyler)
+		Expect(errf()).To(MatchError(ErrUnsupportedRootsType))
+		Expect(slices.Collect(lines)).To(BeEmpty())
+
+		lines, errf = TryLines([]any{tree, 42}, DefaultVisitor, DefaultTreeStyler)
+		Expect(errf()).NotTo(HaveOccurred())
+		for range 2 {
+			Expect(slices.Collect(lines)).To(HaveExactElements(
+				"root", "|  * prop", "+- 1", "`- 2", "   `- 2.1"))
+			Expect(errf()).To(MatchError(ErrUnsupportedNodeType))
+		}
+		for range lines {
+			break
+		}
+		Expect(errf()).NotTo(HaveOccurred())
+
+		lines, errf = TryLines(map[string]any{"label": "m", "properties": 42},
+			DefaultVisitor, DefaultTreeStyler)
+		Expect(slices.Collect(lines)).To(BeEmpty())
+		Expect(errf()).To(MatchError(ErrBadPropertiesField))
+	})
+
+	It("reports unsupported nodes with node information", func() {
+		lines, errf := TryNodeLines([]any{tree, 42}, DefaultVisitor, DefaultTreeStyler)
+		var infos []LineInfo
+		for _, info := range lines {
+			infos = append(infos, info)
+		}
+		Expect(infos).To(HaveLen(5))
+		Expect(errf()).To(MatchError(ErrUnsupportedNodeType))
+
+		_, errf = TryNodeLines(42, DefaultVisitor, DefaultTreeStyler)
+		Expect(errf()).To(MatchError(ErrUnsupportedRootsType))
+	})
+
+	It("produces rendered lines with node information", func() {
+		var lines []string
+		var infos []LineInfo
+		for line, info := range NodeLines(tree, DefaultVisitor, DefaultTreeStyler) {
+			lines = append(lines, line)
+			infos = append(infos, info)
+			if len(lines) == 4 {
+				break
+			}
+		}
+		Expect(lines).To(HaveExactElements("root", "|  * prop", "+- 1", "`- 2"))
+		Expect(infos).To(HaveExactElements(
+			And(HaveField("Depth", 0), HaveField("Kind", LabelLine), HaveField("Node.Label", "root")),
+			And(HaveField("Depth", 0), HaveField("Kind", PropertyLine), HaveField("Node.Label", "root")),
+			And(HaveField("Depth", 1), HaveField("Kind", LabelLine), HaveField("Node.Label", "1")),
+			And(HaveField("Depth", 1), HaveField("Kind", LabelLine), HaveField("Node.Label", "2")),
+		))
+	})
+
+})
@@ -0,0 +1,172 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Property is a hierarchical node property with optional sub-properties,
+// such as a mount with its mount options. Sub-properties are rendered as a
+// sub-tree below their property.
+type Property struct {
+	Text     string
+	Children []Property
+}
+
+// PropertyVisitor is an optional extension to the Visitor interface for
+// visitors that retrieve hierarchical properties of nodes. When a visitor
+// implements this interface, the properties returned by Properties are
+// rendered instead of the flat properties returned by Get.
+type PropertyVisitor interface {
+	Visitor
+	Properties(node any) (properties []Property, err error)
+}
+
+var _ PropertyVisitor = (*MapStructVisitor)(nil)
+
+// Properties returns the hierarchical properties of a tree node, taken from a
+// struct field tagged as `asciitree:"properties"` or from the well-known
+// "properties" map key, see propertySlice for the supported types. Struct
+// fields individually tagged as `asciitree:"property"` additionally
+// contribute “name: value” properties. For a TreeNode, Properties returns its
+// properties without any sub-properties.
+func (v *MapStructVisitor) Properties(node any) ([]Property, error) {
+	_, props, _, err := v.nodeDetails(node, false)
+	if err != nil {
+		return nil, err
+	}
+	if v.SortProperties {
+		props = sortedProperties(props)
+	}
+	return props, nil
+}
+
+// structProperties returns the hierarchical properties of the passed struct
+// node, taken from its properties field (if any), followed by the properties
+// from its individual property fields in field order.
+func structProperties(node reflect.Value, si *structFields) ([]Property, error) {
+	if si.PropertiesPath == nil && si.PropertyFields == nil {
+		return nil, nil
+	}
+	var props []Property
+	if si.PropertiesPath != nil {
+		var err error
+		props, err = propertySlice(node.FieldByIndex(si.PropertiesPath), structLabel(node, si))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if si.PropertyFields == nil {
+		return props, nil
+	}
+	// never append to the properties slice, as it might be the user's own
+	// []Property slice.
+	props = slices.Clip(props)
+	for _, pf := range si.PropertyFields {
+		field := node.FieldByIndex(pf.Path)
+		if pf.OmitEmpty && field.IsZero() {
+			continue
+		}
+		props = append(props, Property{Text: pf.Name + ": " + formatLabel(field, pf.Format)})
+	}
+	return props, nil
+}
+
+// propertySlice returns the hierarchical properties contained in the passed
+// reflect.Value (unpacking an interface value where necessary). It supports
+// []Property, []string, map[string]string with the map entries becoming
+// “key: value” properties sorted by key, as well as slices of fmt.Stringer
+// elements, such as []fmt.Stringer. It returns nil for a nil value, but an
+// ErrBadPropertiesField error for any other type.
+func propertySlice(v reflect.Value, label string) ([]Property, error) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil, nil
+	}
+	switch props := v.Interface().(type) {
+	case []Property:
+		return props, nil
+	case []string:
+		return leafProperties(props), nil
+	case map[string]string:
+		keys := slices.Sorted(maps.Keys(props))
+		texts := make([]string, len(keys))
+		for idx, key := range keys {
+			texts[idx] = key + ": " + props[key]
+		}
+		return leafProperties(texts), nil
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Implements(stringerType) {
+		texts := make([]string, v.Len())
+		for idx := range texts {
+			texts[idx] = formatLabel(v.Index(idx), "")
+		}
+		return leafProperties(texts), nil
+	}
+	err := newNodeError(ErrBadPropertiesField, v.Interface())
+	err.Path = []string{label}
+	return nil, err
+}
+
+var stringerType = reflect.TypeFor[fmt.Stringer]()
+
+// leafProperties returns the passed property texts as properties without
+// any sub-properties.
+func leafProperties(texts []string) []Property {
+	if texts == nil {
+		return nil
+	}
+	props := make([]Property, len(texts))
+	for idx, text := range texts {
+		props[idx] = Property{Text: text}
+	}
+	return props
+}
+
+// propertyTexts returns the texts of the passed properties, leaving out any
+// sub-properties.
+func propertyTexts(props []Property) []string {
+	if props == nil {
+		return nil
+	}
+	texts := make([]string, len(props))
+	for idx, prop := range props {
+		texts[idx] = prop.Text
+	}
+	return texts
+}
+
+// sortedProperties returns a new slice of the passed properties, as well as
+// their sub-properties, sorted lexicographically by their texts.
+func sortedProperties(props []Property) []Property {
+	if props == nil {
+		return nil
+	}
+	sorted := make([]Property, len(props))
+	for idx, prop := range props {
+		sorted[idx] = Property{Text: prop.Text, Children: sortedProperties(prop.Children)}
+	}
+	slices.SortStableFunc(sorted, func(a, b Property) int {
+		return strings.Compare(a.Text, b.Text)
+	})
+	return sorted
+}
@@ -0,0 +1,235 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// countingText counts how often it gets formatted.
+type countingText struct {
+	calls int
+}
+
+func (c *countingText) String() string {
+	c.calls++
+	return "counted"
+}
+
+var _ = Describe("hierarchical properties", func() {
+
+	type PropNode struct {
+		Name     string     `asciitree:"label"`
+		Props    []Property `asciitree:"properties"`
+		Children []PropNode `asciitree:"children"`
+	}
+
+	mount := Property{Text: "mount /data", Children: []Property{
+		{Text: "rw"},
+		{Text: "opts", Children: []Property{{Text: "nosuid"}, {Text: "noexec"}}},
+	}}
+
+	When("visiting", func() {
+
+		It("gets nothing from property-less nodes", func() {
+			Expect(DefaultVisitor.Properties(map[string]any{"label": "foo"})).To(BeEmpty())
+			Expect(DefaultVisitor.Properties(struct{}{})).To(BeEmpty())
+			Expect(DefaultVisitor.Properties(Node{})).To(BeEmpty())
+		})
+
+		It("turns strings into properties", func() {
+			Expect(DefaultVisitor.Properties(Node{Properties: []string{"foo", "bar"}})).To(
+				HaveExactElements(Property{Text: "foo"}, Property{Text: "bar"}))
+		})
+
+		It("gets hierarchical properties", func() {
+			props := []Property{mount, {Text: "foo"}}
+			Expect(DefaultVisitor.Properties(&PropNode{Props: props})).To(Equal(props))
+			Expect(DefaultVisitor.Properties(map[string]any{"properties": props})).To(Equal(props))
+		})
+
+		It("gets only the texts of hierarchical properties in Get", func() {
+			_, props, _ := DefaultVisitor.Get(PropNode{Props: []Property{mount, {Text: "foo"}}})
+			Expect(props).To(HaveExactElements("mount /data", "foo"))
+		})
+
+		It("sorts properties and their sub-properties", func() {
+			props := []Property{{Text: "z", Children: []Property{{Text: "zz"}, {Text: "za"}}}, {Text: "a"}}
+			Expect(NewMapStructVisitor(false, true).Properties(PropNode{Props: props})).To(Equal([]Property{
+				{Text: "a"},
+				{Text: "z", Children: []Property{{Text: "za"}, {Text: "zz"}}},
+			}))
+			Expect(props[0].Children[0].Text).To(Equal("zz"))
+		})
+
+		It("reports bad properties", func() {
+			_, err := DefaultVisitor.Properties(map[string]any{
+				"label":      "foo",
+				"properties": []int{42},
+			})
+			Expect(err).To(MatchError(`unsupported asciitree properties type []int at ["foo"]`))
+			_, err = DefaultVisitor.Properties(42)
+			Expect(err).To(MatchError(ErrUnsupportedNodeType))
+		})
+
+	})
+
+	When("gathering properties from multiple fields", func() {
+
+		type User struct {
+			Name   string         `asciitree:"label"`
+			Groups []fmt.Stringer `asciitree:"properties"`
+			UID    int            `asciitree:"property,name=uid"`
+			Shell  string         `asciitree:"property,omitempty"`
+			Mode   uint32         `asciitree:"property,name=mode,format=%04o"`
+			Addr   net.IP         `asciitree:"property,name=addr,omitempty"`
+		}
+
+		It("finds property fields", func() {
+			Expect(structFieldInfo(reflect.ValueOf(User{}))).To(HaveField("PropertyFields", HaveExactElements(
+				propertyField{Path: []int{2}, Name: "uid"},
+				propertyField{Path: []int{3}, Name: "Shell", OmitEmpty: true},
+				propertyField{Path: []int{4}, Name: "mode", Format: "%04o"},
+				propertyField{Path: []int{5}, Name: "addr", OmitEmpty: true},
+			)))
+		})
+
+		It("gets name-value properties", func() {
+			user := User{
+				Name:   "root",
+				Groups: []fmt.Stringer{color(0), nil},
+				Mode:   0o755,
+				Addr:   net.IPv4(127, 0, 0, 1),
+			}
+			_, props, _ := DefaultVisitor.Get(user)
+			Expect(props).To(HaveExactElements("red", "", "uid: 0", "mode: 0755", "addr: 127.0.0.1"))
+			Expect(DefaultVisitor.Properties(&user)).To(HaveExactElements(
+				Property{Text: "red"}, Property{Text: ""},
+				Property{Text: "uid: 0"}, Property{Text: "mode: 0755"}, Property{Text: "addr: 127.0.0.1"}))
+		})
+
+		It("doesn't modify the properties of nodes", func() {
+			type N struct {
+				Props []Property `asciitree:"properties"`
+				ID    int        `asciitree:"property,name=id"`
+			}
+			props := make([]Property, 1, 10)
+			props[0] = Property{Text: "foo"}
+			Expect(DefaultVisitor.Properties(N{Props: props, ID: 42})).To(HaveExactElements(
+				Property{Text: "foo"}, Property{Text: "id: 42"}))
+			Expect(props[:2]).To(HaveExactElements(Property{Text: "foo"}, Property{}))
+		})
+
+		It("gets properties from string maps and stringer slices", func() {
+			Expect(DefaultVisitor.Properties(map[string]any{
+				"properties": map[string]string{"zz": "1", "aa": "2"},
+			})).To(HaveExactElements(Property{Text: "aa: 2"}, Property{Text: "zz: 1"}))
+			Expect(DefaultVisitor.Properties(map[string]any{
+				"properties": []net.IP{net.IPv4(10, 0, 0, 1)},
+			})).To(HaveExactElements(Property{Text: "10.0.0.1"}))
+		})
+
+		It("renders name-value properties", func() {
+			Expect(Render(User{Name: "nobody", UID: 65534, Shell: "/bin/false"},
+				NewMapStructVisitor(false, true), DefaultTreeStyler)).To(Equal(`nobody
+   * Shell: /bin/false
+   * mode: 0000
+   * uid: 65534
+`))
+		})
+
+		It("formats property fields only once per rendered node", func() {
+			type Counted struct {
+				Name  string        `asciitree:"label"`
+				Count *countingText `asciitree:"property,name=count"`
+			}
+			count := &countingText{}
+			for _, render := range []func(roots any, visitor Visitor, styler *TreeStyler) (string, error){
+				TryRender,
+				func(roots any, visitor Visitor, styler *TreeStyler) (string, error) {
+					return Render(roots, visitor, styler), nil
+				},
+			} {
+				count.calls = 0
+				Expect(render(Counted{Name: "node", Count: count}, DefaultVisitor, DefaultTreeStyler)).To(
+					Equal("node\n   * count: counted\n"))
+				Expect(count.calls).To(Equal(1))
+			}
+		})
+
+	})
+
+	When("rendering", func() {
+
+		tree := PropNode{
+			Name:  "root",
+			Props: []Property{mount, {Text: "plain"}},
+			Children: []PropNode{
+				{Name: "child", Props: []Property{{Text: "x", Children: []Property{{Text: "y"}}}}},
+			},
+		}
+
+		It("renders sub-properties as sub-trees", func() {
+			Expect(Render(tree, DefaultVisitor, LineTreeStyler)).To(Equal(`root
+│  • mount /data
+│    ├─ rw
+│    └─ opts
+│       ├─ nosuid
+│       └─ noexec
+│  • plain
+└─ child
+      • x
+        └─ y
+`))
+		})
+
+		It("keeps sub-properties when decorating nodes", func() {
+			nts := NewTreeStyler(ASCIIStyle)
+			nts.NodeStyler = NodeStylerFunc(func(_ any, _ int, _ []string, label string, props []string) (string, []string) {
+				styledProps := make([]string, len(props))
+				for idx, prop := range props {
+					styledProps[idx] = strings.ToUpper(prop)
+				}
+				return label, styledProps
+			})
+			Expect(Render(tree.Children[0], DefaultVisitor, nts)).To(Equal(`child
+   * X
+     ` + "`" + `- y
+`))
+		})
+
+		It("wraps sub-properties", func() {
+			wts := NewTreeStyler(LineStyle)
+			wts.MaxWidth = 14
+			Expect(Render(PropNode{Name: "root", Props: []Property{
+				{Text: "p", Children: []Property{{Text: "foo bar baz"}, {Text: "last one here"}}},
+			}}, DefaultVisitor, wts)).To(Equal(`root
+   • p
+     ├─ foo bar
+     │  baz
+     └─ last one
+        here
+`))
+		})
+
+	})
+
+})
@@ -0,0 +1,65 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"slices"
+	"sync"
+)
+
+var (
+	stylesMu sync.RWMutex
+	styles   = map[string]TreeStyle{
+		"ascii":   ASCIIStyle,
+		"line":    LineStyle,
+		"heavy":   HeavyStyle,
+		"double":  DoubleStyle,
+		"rounded": RoundedStyle,
+		"dashed":  DashedStyle,
+		"compact": CompactStyle,
+	}
+)
+
+// RegisterStyle registers a TreeStyle under the specified name, replacing any
+// style previously registered under the same name. The predefined styles are
+// registered as "ascii", "line", "heavy", "double", "rounded", "dashed", and
+// "compact". RegisterStyle is safe for concurrent use.
+func RegisterStyle(name string, style TreeStyle) {
+	stylesMu.Lock()
+	defer stylesMu.Unlock()
+	styles[name] = style
+}
+
+// LookupStyle returns the TreeStyle registered under the specified name and
+// true, or the zero TreeStyle and false if there is no such style.
+func LookupStyle(name string) (TreeStyle, bool) {
+	stylesMu.RLock()
+	defer stylesMu.RUnlock()
+	style, ok := styles[name]
+	return style, ok
+}
+
+// StyleNames returns the names of all registered styles in sorted order, for
+// instance, for listing them in command line flag help texts.
+func StyleNames() []string {
+	stylesMu.RLock()
+	defer stylesMu.RUnlock()
+	names := make([]string, 0, len(styles))
+	for name := range styles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
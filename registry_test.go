@@ -0,0 +1,64 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"maps"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("style registry", func() {
+
+	BeforeEach(func() {
+		stylesMu.Lock()
+		saved := maps.Clone(styles)
+		stylesMu.Unlock()
+		DeferCleanup(func() {
+			stylesMu.Lock()
+			styles = saved
+			stylesMu.Unlock()
+		})
+	})
+
+	It("has the predefined styles", func() {
+		Expect(StyleNames()).To(Equal([]string{
+			"ascii", "compact", "dashed", "double", "heavy", "line", "rounded"}))
+		style, ok := LookupStyle("heavy")
+		Expect(ok).To(BeTrue())
+		Expect(style).To(Equal(HeavyStyle))
+	})
+
+	It("doesn't find unknown styles", func() {
+		style, ok := LookupStyle("foobar")
+		Expect(ok).To(BeFalse())
+		Expect(style).To(BeZero())
+	})
+
+	It("registers styles", func() {
+		starStyle := TreeStyle{Fork: "*", Nodeconn: "*", Nofork: "*", Lastnode: "*", Property: "-"}
+		RegisterStyle("stars", starStyle)
+		Expect(StyleNames()).To(ContainElement("stars"))
+		style, ok := LookupStyle("stars")
+		Expect(ok).To(BeTrue())
+		Expect(style).To(Equal(starStyle))
+
+		RegisterStyle("line", ASCIIStyle)
+		style, _ = LookupStyle("line")
+		Expect(style).To(Equal(ASCIIStyle))
+	})
+
+})
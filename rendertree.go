@@ -15,116 +15,616 @@
 package asciitree
 
 import (
-	"fmt"
+	"errors"
+	"io"
 	"iter"
 	"reflect"
+	"slices"
 	"strings"
 )
 
-// renderSubtree returns an iterator that produces lines from recursively
-// rendering the subtree starting at the passed tree node.
+// renderer renders trees using a particular visitor and tree styler, keeping
+// track of the first error encountered while visiting the tree nodes.
+type renderer struct {
+	visitor   Visitor
+	styler    *TreeStyler
+	lenient   bool              // tolerate bad user data as the panicking API always did.
+	err       error             // first error encountered while visiting nodes, if any.
+	ancestors map[nodeID]string // labels of the ancestor nodes currently being rendered.
+	seen      map[nodeID]string // labels of all nodes rendered so far.
+}
+
+// nodeID identifies a node by its pointer identity; this works only for nodes
+// that are pointers or maps.
+type nodeID struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// detailsVisitor is implemented by visitors that retrieve the label, the
+// hierarchical properties followed by the formatted attributes, and the
+// children of a node in a single go, instead of separately visiting the node
+// for its properties and attributes. When lenient, such visitors tolerate
+// certain bad user data in the same way as the panicking API always did; for
+// instance, MapStructVisitor then ignores properties and attributes of map
+// nodes of an unsupported type.
+type detailsVisitor interface {
+	details(node any, lenient bool) (label string, properties []Property, children []any, err error)
+}
+
+// newRenderer returns a new renderer for the specified visitor and styler.
+func newRenderer(visitor Visitor, styler *TreeStyler) *renderer {
+	return &renderer{
+		visitor:   visitor,
+		styler:    styler,
+		ancestors: map[nodeID]string{},
+		seen:      map[nodeID]string{},
+	}
+}
+
+// identity returns the identity of the passed node and true, if the node is a
+// non-nil pointer or map (or a KeyedNode wrapping such a node); otherwise, it
+// returns false, as such nodes cannot form cycles.
+func identity(node any) (nodeID, bool) {
+	v := reflect.ValueOf(nodeOf(node))
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map:
+		if v.IsNil() {
+			return nodeID{}, false
+		}
+		return nodeID{typ: v.Type(), ptr: v.Pointer()}, true
+	}
+	return nodeID{}, false
+}
+
+// get returns the label, properties, and children of the passed node, using
+// the visitor's error path if available. When the visitor additionally
+// supports hierarchical properties, get returns these instead of the flat
+// properties. And when the visitor supports attributes, get appends the
+// formatted attributes to the properties. Visitors retrieving all these node
+// details in a single go are asked to do so instead, tolerating bad user data
+// when lenient. In case of an error, get records the error, completing its
+// path with the passed path of ancestor node labels, and returns false.
+func (r *renderer) get(node any, path []string) (label string, props []Property, children []any, ok bool) {
+	var err error
+	if dv, isDetailsVisitor := r.visitor.(detailsVisitor); isDetailsVisitor {
+		label, props, children, err = dv.details(node, r.lenient)
+	} else {
+		var texts []string
+		if ev, isErrorVisitor := r.visitor.(ErrorVisitor); isErrorVisitor {
+			label, texts, children, err = ev.TryGet(node)
+		} else {
+			label, texts, children = r.visitor.Get(node)
+		}
+		props = leafProperties(texts)
+		if pv, isPropertyVisitor := r.visitor.(PropertyVisitor); isPropertyVisitor && err == nil {
+			props, err = pv.Properties(node)
+		}
+		if av, isAttributeVisitor := r.visitor.(AttributeVisitor); isAttributeVisitor && err == nil {
+			var attrs []KeyValue
+			attrs, err = av.Attributes(node)
+			if len(attrs) != 0 {
+				// never append to the visitor's properties slice, as it might
+				// be the user's own slice.
+				props = slices.Concat(props, leafProperties(formatAttributes(attrs)))
+			}
+		}
+	}
+	if err != nil {
+		r.fail(err, path)
+		return "", nil, nil, false
+	}
+	return label, props, children, true
+}
+
+// annotation returns the annotation of the passed node if the visitor
+// supports annotations, otherwise an empty annotation. In case of an error,
+// annotation records the error and returns false.
+func (r *renderer) annotation(node any, path []string) (string, bool) {
+	av, isAnnotationVisitor := r.visitor.(AnnotationVisitor)
+	if !isAnnotationVisitor {
+		return "", true
+	}
+	annotation, err := av.Annotation(node)
+	if err != nil {
+		r.fail(err, path)
+		return "", false
+	}
+	return annotation, true
+}
+
+// fail records the passed error, completing the path of a *NodeError with the
+// passed path of ancestor node labels.
+func (r *renderer) fail(err error, path []string) {
+	var nerr *NodeError
+	if errors.As(err, &nerr) {
+		nerr.Path = append(slices.Clone(path), nerr.Path...)
+	}
+	r.err = err
+}
+
+// subtree returns an iterator that produces lines from recursively rendering
+// the subtree starting at the passed tree node, together with information
+// about the node each line belongs to.
 //
-// The passed (tree) node can be any value, as long as the passed visitor is
-// able to correctly determine the value's label as well as optional properties
-// and children nodes.
+// The passed (tree) node can be any value, as long as the visitor is able to
+// correctly determine the value's label as well as optional properties and
+// children nodes. If the visitor fails, the iterator records the error and
+// stops producing lines.
 //
-// The styler parameter controls the output rendering, so a user-controllable
-// style can be used while traversing the subtree.
-func renderSubtree(node any, visitor Visitor, styler *TreeStyler) (lines iter.Seq[string]) {
-	label, props, children := visitor.Get(node)
-	return func(yield func(string) bool) {
-		// produce the label of the passed node.
-		if !yield(styler.renderNodeLabel(label)) {
+// The depth parameter specifies the nesting depth of the passed node, with
+// root nodes being at depth 0, while path contains the labels of the
+// ancestor nodes. The column parameter specifies the display column the label
+// of the passed node starts at, as needed for wrapping to the maximum width.
+//
+// Cycles back to ancestor nodes, as well as shared nodes already rendered
+// (when so configured), are rendered as marker lines instead. Multi-line
+// labels and properties get their continuation lines indented so they line up
+// with their first line.
+func (r *renderer) subtree(node any, depth int, column int, path []string) (lines iter.Seq2[string, LineInfo]) {
+	return func(yield func(string, LineInfo) bool) {
+		styler := r.styler
+		id, hasID := identity(node)
+		if hasID {
+			if label, ok := r.ancestors[id]; ok {
+				r.label(styler.renderCycle(label), column,
+					LineInfo{Node: node, Depth: depth, Kind: CycleLine}, yield)
+				return
+			}
+			if label, ok := r.seen[id]; ok {
+				r.label(styler.renderShared(label), column,
+					LineInfo{Node: node, Depth: depth, Kind: SharedLine}, yield)
+				return
+			}
+		}
+		label, props, children, ok := r.get(node, path)
+		if !ok {
 			return
 		}
-		// next, produce the properties of this node.
-		renderProp := styler.renderPropertyChildrenFollowing
-		if len(children) == 0 {
-			renderProp = styler.renderPropertyNoChildrenFollowing
+		if hasID {
+			r.ancestors[id] = label
+			defer delete(r.ancestors, id)
+			if styler.RenderSharedOnce {
+				r.seen[id] = label
+			}
 		}
-		for _, prop := range props {
-			if !yield(renderProp(styler.renderProperty(prop))) {
+		annotation, ok := r.annotation(node, path)
+		if !ok {
+			return
+		}
+		path = append(path, label)
+		// when we've reached the maximum depth, we don't descend any further
+		// but instead only tell how many descendants we're leaving out. If
+		// all children are cycles back to ancestors, then there's nothing
+		// left out at all.
+		elided := 0
+		atMaxDepth := styler.MaxDepth != 0 && depth >= max(styler.MaxDepth, 0)
+		if atMaxDepth && len(children) != 0 {
+			if elided, ok = r.descendants(children, path, map[nodeID]struct{}{}); !ok {
 				return
 			}
+			if elided == 0 {
+				children = nil
+			}
 		}
-		// finally,f or each child subtree of the current tree node we first
+		// give the node styler, if any, the opportunity to decorate the label
+		// and properties of the passed node.
+		styledLabel, styledProps := r.styleNode(node, depth, path, label, props)
+		// produce the label of the passed node.
+		if !r.label(styledLabel, column, LineInfo{Node: node, Depth: depth, Kind: LabelLine, annotation: annotation}, yield) {
+			return
+		}
+		// next, produce the properties of this node.
+		if !r.properties(styledProps, len(children) != 0, column, LineInfo{Node: node, Depth: depth}, yield) {
+			return
+		}
+		if elided != 0 {
+			yield(styler.renderLastNode(styler.renderMarker(styler.renderElision(elided))),
+				LineInfo{Node: node, Depth: depth, Kind: ElisionLine})
+			return
+		}
+		// finally, for each child subtree of the current tree node we first
 		// render these subtrees and then indent the resulting text lines as
 		// needed ... because we have to differentiate between intermediate
 		// child nodes and the final child nodes in each subtree due to
-		// different styling.
-		last := len(children) - 1
-		for idx := range len(children) {
-			lines := renderSubtree(children[idx], visitor, styler)
+		// different styling. If there are too many children, then we render
+		// only the first and optionally last children, with a summary line
+		// in between.
+		head, omitted, tail := elide(children, styler.MaxChildren, styler.TailChildren)
+		if !r.children(head, omitted == 0 && len(tail) == 0, depth, column, path, yield) {
+			return
+		}
+		if omitted != 0 {
 			style := styler.renderBranchedNode
-			styleButFirst := styler.indentLine
-			if idx == last {
+			if len(tail) == 0 {
 				style = styler.renderLastNode
-				styleButFirst = styler.indentLineLastNode
 			}
-			for line := range lines {
-				if !yield(style(line)) {
+			if !yield(style(styler.renderMarker(styler.renderMore(omitted))),
+				LineInfo{Node: node, Depth: depth, Kind: MoreChildrenLine}) {
+				return
+			}
+		}
+		r.children(tail, true, depth, column, path, yield)
+	}
+}
+
+// styleNode returns the label and properties of the passed node as decorated
+// by the node styler, if any; otherwise, it returns the label and properties
+// unchanged. The node styler only gets to decorate the texts of the
+// properties, but not of their sub-properties. When text styles are disabled,
+// styleNode removes any escape sequences the node styler might have added.
+func (r *renderer) styleNode(node any, depth int, path []string, label string, props []Property) (string, []Property) {
+	nodeStyler := r.styler.NodeStyler
+	if nodeStyler == nil {
+		return label, props
+	}
+	label, texts := nodeStyler.StyleNode(node, depth, path, label, propertyTexts(props))
+	styledProps := make([]Property, len(texts))
+	for idx, text := range texts {
+		if r.styler.Color == ColorNever {
+			text = stripEscapes(text)
+		}
+		styledProps[idx].Text = text
+		if idx < len(props) {
+			styledProps[idx].Children = props[idx].Children
+		}
+	}
+	if r.styler.Color == ColorNever {
+		label = stripEscapes(label)
+	}
+	return label, styledProps
+}
+
+// label renders the passed (node) label text starting at the specified
+// column, splitting multi-line text into the label line and continuation
+// lines, and wrapping or truncating lines as configured. Only the first label
+// line carries the annotation of the node, if any. It returns false when yield
+// asked to stop.
+func (r *renderer) label(text string, column int, info LineInfo, yield func(string, LineInfo) bool) bool {
+	adorn := r.styler.renderNodeLabel
+	if info.Kind != LabelLine {
+		adorn = r.styler.renderMarker
+	}
+	for idx, line := range r.styler.fitLines(text, column) {
+		info.Continuation = idx > 0
+		if !yield(adorn(line), info) {
+			return false
+		}
+		info.annotation = ""
+	}
+	return true
+}
+
+// properties renders the passed properties of a node with its label starting
+// at the specified column, leaving out properties as configured and splitting
+// multi-line properties into the property lines and continuation lines, as
+// well as wrapping or truncating them. Sub-properties are rendered as
+// sub-trees below their properties. The childrenFollowing parameter
+// specifies whether the node has children, so that its branch continues
+// alongside the properties. It returns false when yield asked to stop.
+func (r *renderer) properties(props []Property, childrenFollowing bool, column int, info LineInfo, yield func(string, LineInfo) bool) bool {
+	styler := r.styler
+	renderProp := styler.renderPropertyNoChildrenFollowing
+	continueProp := styler.continuePropertyNoChildrenFollowing
+	if childrenFollowing {
+		renderProp = styler.renderPropertyChildrenFollowing
+		continueProp = styler.continuePropertyChildrenFollowing
+	}
+	column += StringWidth(renderProp(""))
+	property := func(prop Property, kind LineKind) bool {
+		info.Kind = kind
+		style := renderProp
+		adorn := styler.renderProperty
+		if kind != PropertyLine {
+			adorn = styler.renderMarker
+		}
+		for idx, line := range styler.fitLines(prop.Text, column) {
+			info.Continuation = idx > 0
+			if !yield(style(adorn(line)), info) {
+				return false
+			}
+			style = continueProp
+		}
+		return r.subproperties(prop.Children, column, continueProp, info, yield)
+	}
+	head, omitted, tail := elide(props, styler.MaxProperties, styler.TailProperties)
+	for _, prop := range head {
+		if !property(prop, PropertyLine) {
+			return false
+		}
+	}
+	if omitted != 0 && !property(Property{Text: styler.renderMore(omitted)}, MorePropertiesLine) {
+		return false
+	}
+	for _, prop := range tail {
+		if !property(prop, PropertyLine) {
+			return false
+		}
+	}
+	return true
+}
+
+// subproperties renders the passed sub-properties as a sub-tree, with the
+// text of the parent property starting at the specified column. The indent
+// function indents the lines of the sub-tree to line up with the text of the
+// parent property. It returns false when yield asked to stop.
+func (r *renderer) subproperties(props []Property, column int, indent func(string) string, info LineInfo, yield func(string, LineInfo) bool) bool {
+	styler := r.styler
+	last := len(props) - 1
+	for idx, prop := range props {
+		style := styler.renderBranchedNode
+		styleButFirst := styler.indentLine
+		if idx == last {
+			style = styler.renderLastNode
+			styleButFirst = styler.indentLineLastNode
+		}
+		for lineIdx, line := range styler.fitLines(prop.Text, column+StringWidth(style(""))) {
+			info.Continuation = lineIdx > 0
+			if !yield(indent(style(styler.renderProperty(line))), info) {
+				return false
+			}
+			style = styleButFirst
+		}
+		nested := func(line string) string { return indent(styleButFirst(line)) }
+		if !r.subproperties(prop.Children, column+StringWidth(styleButFirst("")), nested, info, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// children renders the subtrees of the passed child nodes, indenting their
+// lines. If final is true, the last of the passed child nodes is rendered as
+// the final child of its parent. It returns false when rendering should stop,
+// either because yield asked to stop or because of an error.
+func (r *renderer) children(children []any, final bool, depth int, column int, path []string, yield func(string, LineInfo) bool) bool {
+	styler := r.styler
+	last := len(children) - 1
+	for idx := range len(children) {
+		style := styler.renderBranchedNode
+		styleButFirst := styler.indentLine
+		if final && idx == last {
+			style = styler.renderLastNode
+			styleButFirst = styler.indentLineLastNode
+		}
+		lines := r.subtree(children[idx], depth+1, column+StringWidth(style("")), path)
+		for line, info := range lines {
+			if !yield(style(line), info) {
+				return false
+			}
+			style = styleButFirst
+		}
+		if r.err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// elide splits the passed items into the first maxItems items and the last
+// tailItems items, returning these together with the number of items left out
+// in between. A maxItems of zero or less doesn't leave out any items.
+func elide[T any](items []T, maxItems int, tailItems int) (head []T, omitted int, tail []T) {
+	tailItems = max(tailItems, 0)
+	if maxItems <= 0 || len(items) <= maxItems+tailItems {
+		return items, 0, nil
+	}
+	return items[:maxItems], len(items) - maxItems - tailItems, items[len(items)-tailItems:]
+}
+
+// descendants returns the number of the passed nodes and all their
+// descendants, as determined using the visitor. Nodes reachable via multiple
+// paths are counted only once, and cycles back to ancestor nodes are not
+// counted at all. If the visitor fails, descendants records the error and
+// returns false.
+func (r *renderer) descendants(nodes []any, path []string, visited map[nodeID]struct{}) (count int, ok bool) {
+	for _, node := range nodes {
+		if id, hasID := identity(node); hasID {
+			if _, isAncestor := r.ancestors[id]; isAncestor {
+				continue
+			}
+			if _, isVisited := visited[id]; isVisited {
+				continue
+			}
+			visited[id] = struct{}{}
+		}
+		label, _, children, ok := r.get(node, path)
+		if !ok {
+			return 0, false
+		}
+		subcount, ok := r.descendants(children, append(path, label), visited)
+		if !ok {
+			return 0, false
+		}
+		count += 1 + subcount
+	}
+	return count, true
+}
+
+// forest returns an iterator that produces the lines from rendering all the
+// passed root(s) and their subtrees, together with information about the node
+// each line belongs to. It returns an error if the roots are unsupported.
+func (r *renderer) forest(roots any) (iter.Seq2[string, LineInfo], error) {
+	nodes, err := r.roots(roots)
+	if err != nil {
+		return nil, err
+	}
+	return r.trees(nodes), nil
+}
+
+// trees returns an iterator that produces the lines from rendering the passed
+// (already resolved) root nodes and their subtrees, together with information
+// about the node each line belongs to.
+func (r *renderer) trees(nodes []any) iter.Seq2[string, LineInfo] {
+	return r.annotate(func(yield func(string, LineInfo) bool) {
+		for _, node := range nodes {
+			for line, info := range r.subtree(node, 0, 0, nil) {
+				if !yield(line, info) {
+					return
+				}
+			}
+			if r.err != nil {
+				return
+			}
+		}
+	})
+}
+
+// annotate returns an iterator that produces the passed lines with the node
+// annotations appended to their label lines. When annotations are to be
+// aligned, annotate needs to consume all lines before producing the first
+// line, in order to find the widest annotated label line.
+func (r *renderer) annotate(lines iter.Seq2[string, LineInfo]) iter.Seq2[string, LineInfo] {
+	styler := r.styler
+	if !styler.AlignAnnotations {
+		return func(yield func(string, LineInfo) bool) {
+			for line, info := range lines {
+				if info.annotation != "" {
+					line = styler.renderAnnotation(line, 0, info.annotation)
+					info.annotation = ""
+				}
+				if !yield(line, info) {
 					return
 				}
-				style = styleButFirst
+			}
+		}
+	}
+	return func(yield func(string, LineInfo) bool) {
+		type annotatedLine struct {
+			line string
+			info LineInfo
+		}
+		var annotatedLines []annotatedLine
+		width := 0
+		for line, info := range lines {
+			annotatedLines = append(annotatedLines, annotatedLine{line: line, info: info})
+			if info.annotation != "" {
+				width = max(width, StringWidth(line))
+			}
+		}
+		for _, al := range annotatedLines {
+			line, info := al.line, al.info
+			if info.annotation != "" {
+				line = styler.renderAnnotation(line, width, info.annotation)
+				info.annotation = ""
+			}
+			if !yield(line, info) {
+				return
 			}
 		}
 	}
 }
 
-// Render a tree (or a multi-root “tree” ... is that a forrest?) into a
-// multi-line text string, using the supplied visitor and tree styler.
-//
-// The roots can be specified as a slice of structs, or also as a single struct.
-// In every case, the passed root(s), as well as their subtree nodes need to
-// have two struct fields exported and tagged as `asciitree:"label"` and
-// `asciitree:"children"` respectively.
-//
-// For the visitor, you might want to simply use the DefaultVisitor that handles
-// annotated structs and maps with well-known keys.
+// roots returns the list of root nodes for the passed roots value. A visitor
+// implementing ErrorVisitor fully determines the root nodes itself; for
+// instance, a MapStructVisitor accepts either a slice of root nodes, a single
+// (struct or map) root node, a struct with a roots field, or a map with the
+// well-known key “roots”.
 //
-// As a styler, simply use DefaultTreeStyler, or the slightly more fancyful
-// NewTreeStyler(LineStyle).
-func Render(roots any, visitor Visitor, styler *TreeStyler) string {
+// For other visitors, roots can be either a slice of root nodes passed to the
+// visitor, a single (struct or map) root node, or a map with the well-known
+// key “roots”.
+func (r *renderer) roots(roots any) ([]any, error) {
+	if ev, ok := r.visitor.(ErrorVisitor); ok {
+		return ev.TryRoots(roots)
+	}
 	switch rv := reflect.Indirect(reflect.ValueOf(roots)); rv.Kind() {
 	case reflect.Slice:
 		// For a slice we need to iterate over all elements, passing the interface
 		// of each element to the subtree renderer in turn. Please note that we
 		// put the root element(s) first through the visitor just in case it wants
 		// to sort nodes including root nodes.
-		roots := visitor.Roots(roots)
-		var result strings.Builder
-		for idx := range len(roots) {
-			for line := range renderSubtree(roots[idx], visitor, styler) {
-				result.WriteString(line)
-				result.WriteRune('\n')
-			}
-		}
-		return result.String()
+		return r.visitor.Roots(roots), nil
 	case reflect.Struct:
 		// A single root can be represented via a single struct for convenience,
 		// so simply pass the struct value's interface to the subtree renderer,
 		// and we're done.
-		var result strings.Builder
-		for line := range renderSubtree(roots, visitor, styler) {
-			result.WriteString(line)
-			result.WriteRune('\n')
-		}
-		return result.String()
+		return []any{roots}, nil
 	case reflect.Map:
 		// A map with a "roots" key.
-		maproots := rv.MapIndex(reflect.ValueOf("roots"))
+		maproots := mapValue(rv, "roots")
 		if maproots.Kind() == reflect.Invalid {
-			var result strings.Builder
-			for line := range renderSubtree(roots, visitor, styler) {
-				result.WriteString(line)
-				result.WriteRune('\n')
-			}
-			return result.String()
+			return []any{roots}, nil
 		}
-		return Render(maproots.Interface(), visitor, styler)
+		return r.roots(maproots.Interface())
 	default:
-		panic(fmt.Sprintf("unsupported roots value type: expected slice, map, or struct; got %T", roots))
+		return nil, newNodeError(ErrUnsupportedRootsType, roots)
+	}
+}
+
+// Render a tree (or a multi-root “tree” ... is that a forrest?) into a
+// multi-line text string, using the supplied visitor and tree styler.
+//
+// The roots can be specified as a slice of structs, or also as a single struct.
+// In every case, the passed root(s), as well as their subtree nodes need to
+// have two struct fields exported and tagged as `asciitree:"label"` and
+// `asciitree:"children"` respectively.
+//
+// For the visitor, you might want to simply use the DefaultVisitor that handles
+// annotated structs and maps with well-known keys.
+//
+// As a styler, simply use DefaultTreeStyler, or the slightly more fancyful
+// NewTreeStyler(LineStyle).
+//
+// Render panics when encountering unsupported roots or nodes; use TryRender
+// instead to get an error in these situations. For backwards compatibility,
+// Render ignores properties and attributes of map nodes of an unsupported
+// type, while TryRender reports them.
+func Render(roots any, visitor Visitor, styler *TreeStyler) string {
+	var result strings.Builder
+	if err := render(&result, roots, visitor, styler.plain(nil), true); err != nil {
+		panic(err)
+	}
+	return result.String()
+}
+
+// TryRender works like Render, but returns an error instead of panicking when
+// encountering unsupported roots or nodes. The error then is a *NodeError
+// wrapping ErrUnsupportedRootsType, ErrUnsupportedNodeType, or
+// ErrBadPropertiesField.
+//
+// TryRender relies on the visitor implementing ErrorVisitor, as
+// MapStructVisitor does; otherwise, it is up to the visitor how it handles
+// unsupported nodes.
+func TryRender(roots any, visitor Visitor, styler *TreeStyler) (string, error) {
+	var result strings.Builder
+	if err := render(&result, roots, visitor, styler.plain(nil), false); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// RenderTo works like TryRender, but instead of building the whole rendered
+// tree in memory it streams the rendered lines directly into the passed
+// writer, line by line. RenderTo stops at the first write error or
+// unsupported roots or node and returns the error.
+//
+// As RenderTo issues a separate write for each line, you might want to wrap
+// unbuffered writers, such as os.Stdout, in a bufio.Writer. However, in the
+// default ColorAuto mode, RenderTo renders text styles only when the passed
+// writer is a terminal, so when wrapping a terminal's writer you need to
+// explicitly set the tree styler's Color mode.
+func RenderTo(w io.Writer, roots any, visitor Visitor, styler *TreeStyler) error {
+	return render(w, roots, visitor, styler.plain(w), false)
+}
+
+// render streams the rendered lines into the passed writer, line by line,
+// optionally tolerating bad user data as the panicking API always did.
+func render(w io.Writer, roots any, visitor Visitor, styler *TreeStyler, lenient bool) error {
+	r := newRenderer(visitor, styler)
+	r.lenient = lenient
+	lines, err := r.forest(roots)
+	if err != nil {
+		return err
 	}
+	var buf []byte
+	for line := range lines {
+		buf = append(append(buf[:0], line...), '\n')
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return r.err
 }
 
 // RenderPlain renders a tree or multi-root tree into a multi-line text string
@@ -138,9 +638,21 @@ func RenderPlain(roots any) string {
 	return Render(roots, DefaultVisitor, DefaultTreeStyler)
 }
 
+// RenderPlainTo works like RenderPlain, but streams the rendered lines into
+// the passed writer, returning the first write error, if any.
+func RenderPlainTo(w io.Writer, roots any) error {
+	return RenderTo(w, roots, DefaultVisitor, DefaultTreeStyler)
+}
+
 // RenderFancy works like RenderPlain, rendering a tree or multi-root tree
 // into a multi-line text string, but it uses Unicode box characters to render
 // the branch lines.
 func RenderFancy(roots any) string {
 	return Render(roots, DefaultVisitor, LineTreeStyler)
 }
+
+// RenderFancyTo works like RenderFancy, but streams the rendered lines into
+// the passed writer, returning the first write error, if any.
+func RenderFancyTo(w io.Writer, roots any) error {
+	return RenderTo(w, roots, DefaultVisitor, LineTreeStyler)
+}
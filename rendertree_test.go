@@ -15,6 +15,12 @@
 package asciitree
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
 	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -176,14 +182,544 @@ root1
 		Expect(strings.HasPrefix(text, "root\n")).To(BeTrue())
 	})
 
+	It("renders into a writer", func() {
+		var buf bytes.Buffer
+		Expect(RenderTo(&buf, rootmap2, DefaultVisitor, ts)).To(Succeed())
+		Expect(buf.String()).To(Equal(Render(rootmap2, DefaultVisitor, ts)))
+
+		buf.Reset()
+		Expect(RenderPlainTo(&buf, rootmap2)).To(Succeed())
+		Expect(buf.String()).To(Equal(RenderPlain(rootmap2)))
+
+		buf.Reset()
+		Expect(RenderFancyTo(&buf, rootmap2)).To(Succeed())
+		Expect(buf.String()).To(Equal(RenderFancy(rootmap2)))
+	})
+
+	It("stops rendering at the first write error", func() {
+		w := &failingWriter{okWrites: 2}
+		Expect(RenderTo(w, []Node{rootnode1, rootnode2}, DefaultVisitor, ts)).To(
+			MatchError(errWriteFailed))
+		Expect(w.writes).To(Equal(3))
+		Expect(w.String()).To(Equal("root1\n│  • foo\n"))
+	})
+
+	It("renders roots from a roots field", func() {
+		type Forest struct {
+			Trees []Node `asciitree:"roots"`
+		}
+		Expect(Render(Forest{Trees: []Node{rootnode2}}, DefaultVisitor, ts)).To(Equal(`root2
+└── X
+`))
+	})
+
+	It("handles maps with other key types", func() {
+		type K string
+		Expect(Render(map[K]any{"label": "root", "children": []map[any]any{{"label": "child"}}},
+			DefaultVisitor, DefaultTreeStyler)).To(Equal("root\n`- child\n"))
+
+		_, err := TryRender(map[int]string{1: "a"}, DefaultVisitor, DefaultTreeStyler)
+		Expect(err).To(MatchError(ErrUnsupportedRootsType))
+		_, err = TryRender(map[string]any{"label": "root", "children": []any{map[int]string{1: "a"}}},
+			DefaultVisitor, DefaultTreeStyler)
+		Expect(err).To(MatchError(ErrUnsupportedNodeType))
+		Expect(err).To(HaveField("Path", HaveExactElements("root")))
+	})
+
+	It("renders roots from a typed roots map", func() {
+		type M map[string]any
+		roots := map[string][]M{"roots": {{"label": "a"}, {"label": "b"}}}
+		Expect(Render(roots, DefaultVisitor, DefaultTreeStyler)).To(Equal("a\nb\n"))
+		Expect(TryRender(roots, DefaultVisitor, DefaultTreeStyler)).To(Equal("a\nb\n"))
+	})
+
+	It("resolves single roots itself for plain visitors", func() {
+		v := &sliceRootsVisitor{Visitor: DefaultVisitor}
+		type Forest struct {
+			Trees []Node `asciitree:"roots"`
+		}
+		Expect(Render(rootnode2, v, ts)).To(Equal(Render(rootnode2, DefaultVisitor, ts)))
+		Expect(Render(map[string]any{"roots": []Node{rootnode2}}, v, ts)).To(
+			Equal(Render(rootnode2, DefaultVisitor, ts)))
+		Expect(Render(Forest{}, v, ts)).To(Equal("\n"))
+		Expect(func() { Render(42, v, ts) }).To(PanicWith(MatchError(ErrUnsupportedRootsType)))
+	})
+
 	It("panics when rendering an unsupported roots type", func() {
 		Expect(func() { Render(42, DefaultVisitor, ts) }).To(Panic())
 		Expect(func() { Render([]int{42}, DefaultVisitor, ts) }).To(Panic())
 	})
 
+	It("returns errors instead of panicking", func() {
+		_, err := TryRender(42, DefaultVisitor, ts)
+		Expect(err).To(MatchError(ErrUnsupportedRootsType))
+
+		type M map[string]any
+		_, err = TryRender(M{"label": "root", "children": []any{
+			M{"label": "1"},
+			M{"label": "2", "children": []any{M{"label": "2.1"}, 42}},
+		}}, DefaultVisitor, ts)
+		Expect(err).To(MatchError(ErrUnsupportedNodeType))
+		Expect(err).To(HaveField("Path", HaveExactElements("root", "2")))
+
+		_, err = TryRender([]M{
+			{"label": "root", "children": []any{
+				M{"label": "1", "properties": []int{42}}}},
+			{"label": "not rendered"},
+		}, DefaultVisitor, ts)
+		Expect(err).To(MatchError(ErrBadPropertiesField))
+		Expect(err).To(HaveField("Path", HaveExactElements("root", "1")))
+		Expect(err).To(MatchError(`unsupported asciitree properties type []int at ["root" "1"]`))
+
+		text, err := TryRender(rootmap2, DefaultVisitor, ts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(text).To(Equal(Render(rootmap2, DefaultVisitor, ts)))
+	})
+
+	It("ignores bad map attributes only when panicking", func() {
+		tree := map[string]any{"label": "x", "attributes": []string{"a"}}
+		Expect(Render(tree, DefaultVisitor, DefaultTreeStyler)).To(Equal("x\n"))
+		_, err := TryRender(tree, DefaultVisitor, DefaultTreeStyler)
+		Expect(err).To(MatchError(ErrBadAttributesField))
+
+		type T struct {
+			Label string   `asciitree:"label"`
+			Attrs []string `asciitree:"attributes"`
+		}
+		Expect(func() { Render(T{Label: "x"}, DefaultVisitor, DefaultTreeStyler) }).To(
+			PanicWith(MatchError(ErrBadAttributesField)))
+	})
+
+	It("ignores bad map properties only when panicking", func() {
+		type M map[string]any
+		tree := M{"label": "x", "properties": "oops", "children": []M{{"label": "y"}}}
+		Expect(Render(tree, DefaultVisitor, DefaultTreeStyler)).To(Equal("x\n`- y\n"))
+		Expect(slices.Collect(Lines(tree, DefaultVisitor, DefaultTreeStyler))).To(HaveExactElements("x", "`- y"))
+		Expect((&TreeTable{}).Render(tree, DefaultVisitor, DefaultTreeStyler)).To(Equal("x\n`- y\n"))
+
+		_, err := TryRender(tree, DefaultVisitor, DefaultTreeStyler)
+		Expect(err).To(MatchError(ErrBadPropertiesField))
+		Expect(RenderTo(io.Discard, tree, DefaultVisitor, DefaultTreeStyler)).To(MatchError(ErrBadPropertiesField))
+		_, err = (&TreeTable{}).TryRender(tree, DefaultVisitor, DefaultTreeStyler)
+		Expect(err).To(MatchError(ErrBadPropertiesField))
+
+		type T struct {
+			Label string `asciitree:"label"`
+			Props int    `asciitree:"properties"`
+		}
+		Expect(func() { Render(T{Label: "x"}, DefaultVisitor, DefaultTreeStyler) }).To(
+			PanicWith(MatchError(ErrBadPropertiesField)))
+	})
+
+	It("stops writing at the first unsupported node", func() {
+		var buf bytes.Buffer
+		Expect(RenderTo(&buf, []any{rootnode2, 42, rootnode1}, DefaultVisitor, ts)).To(
+			MatchError(ErrUnsupportedNodeType))
+		Expect(buf.String()).To(Equal("root2\n└── X\n"))
+	})
+
+	It("renders cycles back to ancestors", func() {
+		a := &Node{Name: "a"}
+		b := &Node{Name: "b", Subnodes: []*Node{a, {Name: "c"}}}
+		a.Subnodes = []*Node{b}
+		Expect(Render(a, DefaultVisitor, ts)).To(Equal(`a
+└── b
+    ├── ↻ (cycle to a)
+    └── c
+`))
+
+		type M map[string]any
+		m := M{"label": "m"}
+		m["children"] = []M{m}
+		Expect(RenderFancy(m)).To(Equal(`m
+└─ ↻ (cycle to m)
+`))
+
+		cts := NewTreeStyler(ASCIIStyle)
+		cts.CycleMarker = "^%s"
+		Expect(Render(m, DefaultVisitor, cts)).To(Equal("m\n`- ^m\n"))
+	})
+
+	It("renders shared subtrees only once", func() {
+		shared := &Node{Name: "shared", Subnodes: []*Node{{Name: "leaf"}}}
+		root := Node{Name: "root", Subnodes: []*Node{shared, {Name: "x", Subnodes: []*Node{shared}}}}
+		Expect(Render(root, DefaultVisitor, ts)).To(Equal(`root
+├── shared
+│   └── leaf
+└── x
+    └── shared
+        └── leaf
+`))
+
+		sts := NewTreeStyler(LineStyle)
+		sts.ChildIndent = 4
+		sts.RenderSharedOnce = true
+		var infos []LineInfo
+		for _, info := range NodeLines([]*Node{&root, shared}, DefaultVisitor, sts) {
+			infos = append(infos, info)
+		}
+		Expect(Render([]*Node{&root, shared}, DefaultVisitor, sts)).To(Equal(`root
+├── shared
+│   └── leaf
+└── x
+    └── shared (see above)
+shared (see above)
+`))
+		Expect(infos[4].Kind).To(Equal(SharedLine))
+	})
+
+	It("limits the depth", func() {
+		dts := NewTreeStyler(LineStyle)
+		dts.ChildIndent = 4
+		dts.MaxDepth = 1
+		Expect(Render([]Node{rootnode1, rootnode2}, DefaultVisitor, dts)).To(Equal(`root1
+│  • foo
+│  • bar
+├── 1
+├── 2
+│   └── … 2 more descendant(s)
+└── 3
+    └── … 1 more descendant(s)
+root2
+└── X
+`))
+
+		dts.MaxDepth = 0
+		Expect(Render(rootmap2, DefaultVisitor, dts)).To(
+			Equal(Render(rootmap2, DefaultVisitor, ts)))
+
+		type M map[string]any
+		m := M{"label": "m", "properties": []string{"p"}}
+		m["children"] = []M{{"label": "a", "children": []M{m, {"label": "b"}}}}
+		dts.MaxDepth = 0
+		dts.DepthElision = "(%d hidden)"
+		Expect(Render(M{"label": "root", "children": []M{m}}, DefaultVisitor, dts)).To(Equal(`root
+└── m
+    │  • p
+    └── a
+        ├── ↻ (cycle to m)
+        └── b
+`))
+		dts.MaxDepth = 1
+		Expect(Render(M{"label": "root", "children": []M{m}}, DefaultVisitor, dts)).To(Equal(`root
+└── m
+    │  • p
+    └── (2 hidden)
+`))
+
+		dts.MaxDepth = RootsOnly
+		Expect(Render([]Node{rootnode1, rootnode2}, DefaultVisitor, dts)).To(Equal(`root1
+│  • foo
+│  • bar
+└── (6 hidden)
+root2
+└── (1 hidden)
+`))
+	})
+
+	It("doesn't elide cycles at the maximum depth", func() {
+		type M map[string]any
+		root := M{"label": "root"}
+		root["children"] = []M{{"label": "a", "properties": []string{"p"}, "children": []M{root}}}
+		dts := NewTreeStyler(LineStyle)
+		dts.MaxDepth = 1
+		Expect(Render(root, DefaultVisitor, dts)).To(Equal(`root
+└─ a
+      • p
+`))
+	})
+
+	It("reports errors when counting elided descendants", func() {
+		type M map[string]any
+		dts := NewTreeStyler(LineStyle)
+		dts.MaxDepth = 1
+		_, err := TryRender(M{"label": "root", "children": []M{
+			{"label": "a", "children": []any{M{"label": "b", "children": []any{42}}}},
+		}}, DefaultVisitor, dts)
+		Expect(err).To(And(
+			MatchError(ErrUnsupportedNodeType),
+			HaveField("Path", HaveExactElements("root", "a", "b"))))
+	})
+
+	It("limits the number of children and properties", func() {
+		type M map[string]any
+		var children []M
+		for idx := range 6 {
+			children = append(children, M{"label": fmt.Sprintf("c%d", idx+1)})
+		}
+		tree := M{
+			"label":      "root",
+			"properties": []string{"p1", "p2", "p3", "p4"},
+			"children":   children,
+		}
+
+		lts := NewTreeStyler(LineStyle)
+		lts.ChildIndent = 4
+		lts.MaxChildren = 2
+		lts.MaxProperties = 1
+		Expect(Render(tree, DefaultVisitor, lts)).To(Equal(`root
+│  • p1
+│  • … and 3 more
+├── c1
+├── c2
+└── … and 4 more
+`))
+
+		lts.TailChildren = 1
+		lts.TailProperties = 2
+		lts.More = "(%d more)"
+		Expect(Render(tree, DefaultVisitor, lts)).To(Equal(`root
+│  • p1
+│  • (1 more)
+│  • p3
+│  • p4
+├── c1
+├── c2
+├── (3 more)
+└── c6
+`))
+
+		lts.MaxChildren = 5
+		lts.MaxProperties = 3
+		Expect(Render(tree, DefaultVisitor, lts)).To(
+			Equal(Render(tree, DefaultVisitor, ts)))
+
+		var kinds []LineKind
+		lts.MaxChildren = 1
+		lts.TailChildren = 0
+		lts.MaxProperties = 1
+		lts.TailProperties = 0
+		for _, info := range NodeLines(tree, DefaultVisitor, lts) {
+			kinds = append(kinds, info.Kind)
+		}
+		Expect(kinds).To(HaveExactElements(
+			LabelLine, PropertyLine, MorePropertiesLine, LabelLine, MoreChildrenLine))
+	})
+
+	It("renders multi-line labels and properties", func() {
+		type M map[string]any
+		tree := M{
+			"label":      "root\nsecond root line\n",
+			"properties": []string{"prop\r\nsecond prop line"},
+			"children": []M{
+				{"label": "1\nfirst", "children": []M{
+					{"label": "1.1\nfirst-first", "properties": []string{"p\nq"}},
+				}},
+				{"label": "2\nsecond", "properties": []string{"p\nq"}},
+			},
+		}
+		Expect(Render(tree, DefaultVisitor, ts)).To(Equal(`root
+second root line
+│  • prop
+│    second prop line
+├── 1
+│   first
+│   └── 1.1
+│       first-first
+│          • p
+│            q
+└── 2
+    second
+       • p
+         q
+`))
+
+		var conts []bool
+		for _, info := range NodeLines(tree, DefaultVisitor, ts) {
+			conts = append(conts, info.Continuation)
+		}
+		Expect(conts[:4]).To(HaveExactElements(false, true, false, true))
+	})
+
+	It("wraps and truncates labels and properties", func() {
+		type M map[string]any
+		tree := M{
+			"label":      "the root node with a long label",
+			"properties": []string{"a rather long property"},
+			"children": []M{
+				{"label": "first child with a long label", "children": []M{
+					{"label": "grandchild", "properties": []string{"some long property"}},
+				}},
+				{"label": "second child with a long label"},
+			},
+		}
+		wts := NewTreeStyler(LineStyle)
+		wts.ChildIndent = 4
+		wts.MaxWidth = 20
+		Expect(Render(tree, DefaultVisitor, wts)).To(Equal(`the root node with a
+long label
+│  • a rather long
+│    property
+├── first child with
+│   a long label
+│   └── grandchild
+│          • some
+│            long
+│            property
+└── second child
+    with a long
+    label
+`))
+
+		wts.Truncate = true
+		Expect(Render(tree, DefaultVisitor, wts)).To(Equal(`the root node with …
+│  • a rather long …
+├── first child wit…
+│   └── grandchild
+│          • some lo…
+└── second child wi…
+`))
+	})
+
+	DescribeTable("rendering with the predefined tree stylers",
+		func(styler *TreeStyler, expected string) {
+			tree := M{"label": "root", "properties": []string{"p"}, "children": []M{
+				{"label": "a", "children": []M{
+					{"label": "a.1", "properties": []string{"q"}},
+				}},
+				{"label": "b"},
+			}}
+			Expect(Render(tree, DefaultVisitor, styler)).To(Equal(expected))
+		},
+		Entry("ASCII", DefaultTreeStyler, `root
+|  * p
++- a
+|  `+"`"+`- a.1
+|        * q
+`+"`"+`- b
+`),
+		Entry("line", LineTreeStyler, `root
+│  • p
+├─ a
+│  └─ a.1
+│        • q
+└─ b
+`),
+		Entry("heavy", HeavyTreeStyler, `root
+┃  • p
+┣━ a
+┃  ┗━ a.1
+┃        • q
+┗━ b
+`),
+		Entry("double", DoubleTreeStyler, `root
+║  • p
+╠═ a
+║  ╚═ a.1
+║        • q
+╚═ b
+`),
+		Entry("rounded", RoundedTreeStyler, `root
+│  • p
+├─ a
+│  ╰─ a.1
+│        • q
+╰─ b
+`),
+		Entry("dashed", DashedTreeStyler, `root
+┊  • p
+├┄ a
+┊  └┄ a.1
+┊        • q
+└┄ b
+`),
+		Entry("compact", CompactTreeStyler, `root
+     p
+   a
+      a.1
+           q
+   b
+`),
+	)
+
+	It("decorates individual nodes", func() {
+		type M map[string]any
+		tree := M{"label": "root", "children": []M{
+			{"label": "ok"},
+			{"label": "failed", "properties": []string{"boom"}},
+		}}
+		var depths []int
+		var paths [][]string
+		nts := NewTreeStyler(ASCIIStyle)
+		nts.NodeStyler = NodeStylerFunc(func(node any, depth int, path []string, label string, props []string) (string, []string) {
+			depths = append(depths, depth)
+			paths = append(paths, slices.Clone(path))
+			if label != "failed" {
+				return label, props
+			}
+			styledProps := make([]string, len(props))
+			for idx, prop := range props {
+				styledProps[idx] = Red.Apply(prop)
+			}
+			return Styles(Bold, Red).Apply(label), styledProps
+		})
+		nts.Color = ColorAlways
+		Expect(Render(tree, DefaultVisitor, nts)).To(Equal(
+			"root\n+- ok\n`- \x1b[1;31mfailed\x1b[0m\n      * \x1b[31mboom\x1b[0m\n"))
+		Expect(depths).To(HaveExactElements(0, 1, 1))
+		Expect(paths).To(HaveExactElements(
+			HaveExactElements("root"),
+			HaveExactElements("root", "ok"),
+			HaveExactElements("root", "failed")))
+
+		nts.Color = ColorNever
+		Expect(Render(tree, DefaultVisitor, nts)).To(Equal(
+			"root\n+- ok\n`- failed\n      * boom\n"))
+	})
+
+	It("keeps node styles within wrapped and truncated lines", func() {
+		type M map[string]any
+		tree := M{"label": "a very long label", "children": []M{{"label": "child"}}}
+		nts := *NewTreeStyler(ASCIIStyle)
+		nts.NodeStyler = NodeStylerFunc(func(node any, depth int, path []string, label string, props []string) (string, []string) {
+			return Red.Apply(label), props
+		})
+		nts.Color = ColorAlways
+		nts.MaxWidth = 12
+		Expect(Render(tree, DefaultVisitor, &nts)).To(Equal(
+			"\x1b[31ma very long\x1b[0m\n" +
+				"\x1b[31mlabel\x1b[0m\n" +
+				"`- \x1b[31mchild\x1b[0m\n"))
+
+		nts.Truncate = true
+		Expect(Render(tree, DefaultVisitor, &nts)).To(Equal(
+			"\x1b[31ma very long…\x1b[0m\n" +
+				"`- \x1b[31mchild\x1b[0m\n"))
+	})
+
 	It("renders nothing when given a bad node", func() {
 		type badNode struct{}
 		Expect(Render(badNode{}, DefaultVisitor, ts)).To(Equal("\n"))
 	})
 
 })
+
+// sliceRootsVisitor is a plain visitor that supports only slices of roots.
+type sliceRootsVisitor struct {
+	Visitor
+}
+
+func (v *sliceRootsVisitor) Roots(roots any) []any {
+	if reflect.ValueOf(roots).Kind() != reflect.Slice {
+		panic("unsupported roots")
+	}
+	return v.Visitor.Roots(roots)
+}
+
+var errWriteFailed = errors.New("write failed")
+
+// failingWriter accepts a specified number of writes and then fails all
+// further writes.
+type failingWriter struct {
+	strings.Builder
+	okWrites int
+	writes   int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.writes > w.okWrites {
+		return 0, errWriteFailed
+	}
+	return w.Builder.Write(p)
+}
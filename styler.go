@@ -15,6 +15,8 @@
 package asciitree
 
 import (
+	"fmt"
+	"io"
 	"strings"
 )
 
@@ -48,14 +50,161 @@ var LineStyle = TreeStyle{
 	Property: "•",
 }
 
+// HeavyStyle styles ASCII trees using heavy Unicode line characters.
+var HeavyStyle = TreeStyle{
+	Fork:     "┣",
+	Nodeconn: "━",
+	Nofork:   "┃",
+	Lastnode: "┗",
+	Property: "•",
+}
+
+// DoubleStyle styles ASCII trees using double Unicode line characters.
+var DoubleStyle = TreeStyle{
+	Fork:     "╠",
+	Nodeconn: "═",
+	Nofork:   "║",
+	Lastnode: "╚",
+	Property: "•",
+}
+
+// RoundedStyle styles ASCII trees using Unicode line characters with the
+// last branch ending in a rounded corner.
+var RoundedStyle = TreeStyle{
+	Fork:     "├",
+	Nodeconn: "─",
+	Nofork:   "│",
+	Lastnode: "╰",
+	Property: "•",
+}
+
+// DashedStyle styles ASCII trees using dashed Unicode line characters.
+var DashedStyle = TreeStyle{
+	Fork:     "├",
+	Nodeconn: "┄",
+	Nofork:   "┊",
+	Lastnode: "└",
+	Property: "•",
+}
+
+// CompactStyle styles ASCII trees using only indentation, without any
+// branches or property bullets.
+var CompactStyle = TreeStyle{
+	Fork:     " ",
+	Nodeconn: " ",
+	Nofork:   " ",
+	Lastnode: " ",
+	Property: " ",
+}
+
 // TreeStyler describes the tree branch and node properties indentations, as
 // well as the style of "line art" to use when rendering ASCII trees.
+//
+// When MaxDepth is set, only nodes down to this depth are rendered, with an
+// elision line telling the number of descendants left out below the nodes at
+// the maximum depth. As a MaxDepth of 0 means unlimited depth, set MaxDepth to
+// RootsOnly in order to render only the root nodes.
+//
+// Similarly, MaxChildren and MaxProperties limit the number of children and
+// properties rendered per node, optionally additionally rendering the last
+// TailChildren and TailProperties. A summary line then tells how many
+// children or properties have been left out.
+//
+// When MaxWidth is set, labels and properties are word-wrapped to the width
+// remaining after their branch prefixes, with the wrapped lines lining up
+// with the first line. Alternatively, labels and properties can be truncated.
+//
+// Node annotations, such as sizes or statuses, are rendered right of the
+// first label line of their nodes. With AlignAnnotations set, all annotations
+// line up in a common column, at the cost of rendering the whole tree before
+// producing the first line.
+//
+// Branches, labels, properties, and markers can be styled with colors and
+// text attributes, which are rendered as ANSI escape sequences. In the default
+// ColorAuto mode, text styles are rendered only when the NO_COLOR environment
+// variable isn't set and (where known) when rendering into a terminal. For
+// decorating individual nodes differently, set a NodeStyler.
+//
+// Indentations are based on the display width of the TreeStyle elements (see
+// StringWidth), so that even wide characters in styles line up properly.
+//
+// Nodes referenced via pointers or nodes that are maps are tracked while
+// rendering, so that cycles back to ancestor nodes are rendered using the
+// CycleMarker instead of looping endlessly. Optionally, subtrees shared
+// between several parent nodes can be rendered only once, with later
+// occurrences rendered using the SharedMarker.
 type TreeStyler struct {
-	Style       TreeStyle // The specific TreeStyle to use, such as ASCIIStyle, or LineStyle.
-	ChildIndent int       // The indentation of child nodes.
-	PropIndent  int       // The indentation of properties w.r.t. their node
+	Style            TreeStyle  // The specific TreeStyle to use, such as ASCIIStyle, or LineStyle.
+	ChildIndent      int        // The indentation of child nodes.
+	PropIndent       int        // The indentation of properties w.r.t. their node
+	CycleMarker      string     // Format for a cycle back to the ancestor node labelled %s; defaults to DefaultCycleMarker.
+	SharedMarker     string     // Format for a shared node labelled %s that was rendered above; defaults to DefaultSharedMarker.
+	RenderSharedOnce bool       // Render shared subtrees only once, referencing them later using the SharedMarker.
+	MaxDepth         int        // Maximum depth of nodes to render, with roots at depth 0; 0 for unlimited depth, RootsOnly for only the roots.
+	DepthElision     string     // Format for the %d descendants left out below nodes at MaxDepth; defaults to DefaultDepthElision.
+	MaxChildren      int        // Maximum number of first children to render per node; 0 for unlimited children.
+	TailChildren     int        // Number of last children to additionally render when leaving out children.
+	MaxProperties    int        // Maximum number of first properties to render per node; 0 for unlimited properties.
+	TailProperties   int        // Number of last properties to additionally render when leaving out properties.
+	More             string     // Format for the %d children or properties left out; defaults to DefaultMore.
+	MaxWidth         int        // Maximum width of rendered lines to wrap labels and properties to; 0 for unlimited width.
+	Truncate         bool       // Truncate labels and properties with an ellipsis at MaxWidth instead of wrapping them.
+	BranchStyle      TextStyle  // Text style of the branch and property glyphs.
+	LabelStyle       TextStyle  // Text style of node labels.
+	PropertyStyle    TextStyle  // Text style of properties.
+	MarkerStyle      TextStyle  // Text style of cycle, shared, elision, and summary markers.
+	AnnotationStyle  TextStyle  // Text style of node annotations.
+	AlignAnnotations bool       // Line up node annotations in a common column right of the widest label line.
+	Color            ColorMode  // Whether to render text styles; defaults to ColorAuto.
+	NodeStyler       NodeStyler // Optional per-node decoration of labels and properties.
+}
+
+// NodeStyler decorates the labels and properties of individual nodes, for
+// instance, highlighting failed nodes in red, or dimming skipped nodes.
+//
+// StyleNode gets passed the node, its nesting depth with root nodes at depth
+// 0, the labels of the nodes along the path from the root down to and
+// including the node, as well as its label and properties. It returns the
+// decorated label and properties. StyleNode must neither modify nor retain
+// the passed path and properties.
+type NodeStyler interface {
+	StyleNode(node any, depth int, path []string, label string, properties []string) (styledLabel string, styledProperties []string)
+}
+
+// NodeStylerFunc adapts an ordinary function to the NodeStyler interface.
+type NodeStylerFunc func(node any, depth int, path []string, label string, properties []string) (string, []string)
+
+// StyleNode calls f(node, depth, path, label, properties).
+func (f NodeStylerFunc) StyleNode(node any, depth int, path []string, label string, properties []string) (string, []string) {
+	return f(node, depth, path, label, properties)
 }
 
+// minFitWidth is the minimum width to wrap or truncate labels and properties
+// to, even if this exceeds the maximum width in deeply nested trees.
+const minFitWidth = 8
+
+// annotationGap separates node annotations from their label lines.
+const annotationGap = "  "
+
+// The default formats for rendering cycles and shared subtrees, getting passed
+// the label of the node referenced.
+const (
+	DefaultCycleMarker  = "↻ (cycle to %s)"
+	DefaultSharedMarker = "%s (see above)"
+)
+
+// RootsOnly is the MaxDepth for rendering only the root nodes, with their
+// descendants left out.
+const RootsOnly = -1
+
+// DefaultDepthElision is the default format for rendering the number of
+// descendants left out below a node at the maximum depth.
+const DefaultDepthElision = "… %d more descendant(s)"
+
+// DefaultMore is the default format for rendering the number of children or
+// properties left out.
+const DefaultMore = "… and %d more"
+
 // DefaultTreeStyler offers a pure ASCII tree styler, using only "safe"
 // ASCII characters, but no Unicode characters. Ideal for the lovers of
 // unwatered ASCII art.
@@ -65,6 +214,22 @@ var DefaultTreeStyler = NewTreeStyler(ASCIIStyle)
 // branches.
 var LineTreeStyler = NewTreeStyler(LineStyle)
 
+// HeavyTreeStyler uses heavy Unicode box characters to draw tree branches.
+var HeavyTreeStyler = NewTreeStyler(HeavyStyle)
+
+// DoubleTreeStyler uses double Unicode box characters to draw tree branches.
+var DoubleTreeStyler = NewTreeStyler(DoubleStyle)
+
+// RoundedTreeStyler uses Unicode box characters with rounded last branches.
+var RoundedTreeStyler = NewTreeStyler(RoundedStyle)
+
+// DashedTreeStyler uses dashed Unicode box characters to draw tree branches.
+var DashedTreeStyler = NewTreeStyler(DashedStyle)
+
+// CompactTreeStyler renders trees only by indentation, without any branch
+// glyphs.
+var CompactTreeStyler = NewTreeStyler(CompactStyle)
+
 // NewTreeStyler returns a Style object suitable for use with rendering trees.
 func NewTreeStyler(style TreeStyle) *TreeStyler {
 	s := new(TreeStyler)
@@ -74,55 +239,165 @@ func NewTreeStyler(style TreeStyle) *TreeStyler {
 	return s
 }
 
-// Defaults to no adornments to node labels
+// renderCycle returns the (unadorned) label text for a cycle back to the
+// ancestor node with the specified label.
+func (s *TreeStyler) renderCycle(label string) string {
+	if s.CycleMarker == "" {
+		return fmt.Sprintf(DefaultCycleMarker, label)
+	}
+	return fmt.Sprintf(s.CycleMarker, label)
+}
+
+// renderShared returns the (unadorned) label text for a reference to an already
+// rendered shared node with the specified label.
+func (s *TreeStyler) renderShared(label string) string {
+	if s.SharedMarker == "" {
+		return fmt.Sprintf(DefaultSharedMarker, label)
+	}
+	return fmt.Sprintf(s.SharedMarker, label)
+}
+
+// renderElision returns the elision line text telling about the specified
+// number of descendants left out.
+func (s *TreeStyler) renderElision(count int) string {
+	if s.DepthElision == "" {
+		return fmt.Sprintf(DefaultDepthElision, count)
+	}
+	return fmt.Sprintf(s.DepthElision, count)
+}
+
+// renderMore returns the summary line text telling about the specified number
+// of children or properties left out.
+func (s *TreeStyler) renderMore(count int) string {
+	if s.More == "" {
+		return fmt.Sprintf(DefaultMore, count)
+	}
+	return fmt.Sprintf(s.More, count)
+}
+
+// fitLines splits the passed label or property text into its individual
+// lines, wrapping or truncating them so they fit into the maximum width when
+// starting at the specified column. Text styles, such as added by a
+// NodeStyler, are reset at the end of each line and reapplied on the next
+// line.
+func (s *TreeStyler) fitLines(text string, column int) []string {
+	lines := splitLines(text)
+	if s.MaxWidth <= 0 {
+		return carryStyles(lines)
+	}
+	width := max(s.MaxWidth-column, minFitWidth)
+	if s.Truncate {
+		for idx, line := range lines {
+			lines[idx] = truncateLine(line, width)
+		}
+		return carryStyles(lines)
+	}
+	fitted := make([]string, 0, len(lines))
+	for _, line := range lines {
+		fitted = append(fitted, wrapLine(line, width)...)
+	}
+	return carryStyles(fitted)
+}
+
+// plain returns the tree styler to use when rendering into the passed writer:
+// either this tree styler, or a copy without any text styles when text styles
+// should not be rendered. A nil writer indicates an unknown destination.
+func (s *TreeStyler) plain(w io.Writer) *TreeStyler {
+	if colorsEnabled(s.Color, w) {
+		return s
+	}
+	plain := *s
+	plain.BranchStyle = ""
+	plain.LabelStyle = ""
+	plain.PropertyStyle = ""
+	plain.MarkerStyle = ""
+	plain.AnnotationStyle = ""
+	plain.Color = ColorNever
+	return &plain
+}
+
+// Defaults to no adornments to node labels other than the label style.
 func (s *TreeStyler) renderNodeLabel(label string) string {
-	return label
+	return s.LabelStyle.Apply(label)
+}
+
+// renderMarker adorns the text of cycle, shared, elision, and summary lines.
+func (s *TreeStyler) renderMarker(text string) string {
+	return s.MarkerStyle.Apply(text)
+}
+
+// renderAnnotation appends the passed annotation to the label line, padding
+// the label line to the specified display width first.
+func (s *TreeStyler) renderAnnotation(line string, width int, annotation string) string {
+	return line +
+		repeat(" ", width-StringWidth(line)) +
+		annotationGap +
+		s.AnnotationStyle.Apply(annotation)
 }
 
 func (s *TreeStyler) renderBranchedNode(label string) string {
-	return s.Style.Fork +
-		repeat(s.Style.Nodeconn, s.ChildIndent-2) +
+	return s.BranchStyle.Apply(s.Style.Fork+
+		repeat(s.Style.Nodeconn, s.ChildIndent-2)) +
 		" " +
 		label
 }
 
 func (s *TreeStyler) renderLastNode(label string) string {
-	return s.Style.Lastnode +
-		repeat(s.Style.Nodeconn, s.ChildIndent-2) +
+	return s.BranchStyle.Apply(s.Style.Lastnode+
+		repeat(s.Style.Nodeconn, s.ChildIndent-2)) +
 		" " +
 		label
 }
 
+// indentLine indents a line belonging to an intermediate child node, lining
+// up with the label of the child node.
 func (s *TreeStyler) indentLine(line string) string {
-	return s.Style.Nofork +
-		repeat(" ", s.ChildIndent-2) +
-		" " +
+	return s.BranchStyle.Apply(s.Style.Nofork) +
+		repeat(" ", StringWidth(s.renderBranchedNode(""))-StringWidth(s.Style.Nofork)) +
 		line
 }
 
+// indentLineLastNode indents a line belonging to the final child node, lining
+// up with the label of the child node.
 func (s *TreeStyler) indentLineLastNode(line string) string {
-	return repeat(" ", s.ChildIndent) + line
+	return repeat(" ", StringWidth(s.renderLastNode(""))) + line
 }
 
 func (s *TreeStyler) renderProperty(prop string) string {
-	return prop
+	return s.PropertyStyle.Apply(prop)
 }
 
 func (s *TreeStyler) renderPropertyNoChildrenFollowing(prop string) string {
 	return repeat(" ", s.PropIndent) +
-		s.Style.Property +
+		s.BranchStyle.Apply(s.Style.Property) +
 		" " +
 		prop
 }
 
 func (s *TreeStyler) renderPropertyChildrenFollowing(prop string) string {
-	return s.Style.Nofork +
-		repeat(" ", s.PropIndent-1) +
-		s.Style.Property +
+	return s.BranchStyle.Apply(s.Style.Nofork) +
+		repeat(" ", s.PropIndent-StringWidth(s.Style.Nofork)) +
+		s.BranchStyle.Apply(s.Style.Property) +
 		" " +
 		prop
 }
 
+// continuePropertyNoChildrenFollowing renders a continuation line of a
+// multi-line property, lining up with the property text above.
+func (s *TreeStyler) continuePropertyNoChildrenFollowing(prop string) string {
+	return repeat(" ", s.PropIndent+StringWidth(s.Style.Property)+1) +
+		prop
+}
+
+// continuePropertyChildrenFollowing renders a continuation line of a
+// multi-line property of a node with children, lining up with the property
+// text above.
+func (s *TreeStyler) continuePropertyChildrenFollowing(prop string) string {
+	return s.BranchStyle.Apply(s.Style.Nofork) +
+		repeat(" ", s.PropIndent-StringWidth(s.Style.Nofork)+StringWidth(s.Style.Property)+1) +
+		prop
+}
+
 // Like strings.Repeat, but without its panic if count is less than
 // zero.
 func repeat(s string, count int) string {
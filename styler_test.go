@@ -57,6 +57,26 @@ var _ = Describe("Styler", func() {
 			Expect(s.indentLine(s.renderBranchedNode("foo"))).To(Equal("|   +-- foo"))
 			Expect(s.renderPropertyNoChildrenFollowing("proo")).To(Equal("    * proo"))
 			Expect(s.renderPropertyNoChildrenFollowing("proo")).To(Equal("    * proo"))
+			Expect(s.continuePropertyNoChildrenFollowing("proo")).To(Equal("      proo"))
+			Expect(s.continuePropertyChildrenFollowing("proo")).To(Equal("|     proo"))
+		})
+
+		It("lines up wide glyphs", func() {
+			s := NewTreeStyler(TreeStyle{
+				Fork:     "├",
+				Nodeconn: "─",
+				Nofork:   "｜",
+				Lastnode: "└",
+				Property: "★",
+			})
+			s.ChildIndent = 4
+			s.PropIndent = 4
+			Expect(s.renderBranchedNode("foo")).To(Equal("├── foo"))
+			Expect(s.indentLine("foo")).To(Equal("｜  foo"))
+			Expect(s.indentLineLastNode("foo")).To(Equal("    foo"))
+			Expect(s.renderPropertyChildrenFollowing("proo")).To(Equal("｜  ★ proo"))
+			Expect(s.continuePropertyChildrenFollowing("proo")).To(Equal("｜    proo"))
+			Expect(s.continuePropertyNoChildrenFollowing("proo")).To(Equal("      proo"))
 		})
 	})
 
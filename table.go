@@ -0,0 +1,193 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"io"
+	"strings"
+)
+
+// Alignment specifies how to align the values in a table column.
+type Alignment int
+
+// The alignments of table columns.
+const (
+	AlignLeft  Alignment = iota // align values at the left of the column.
+	AlignRight                  // align values at the right of the column.
+)
+
+// Column defines a data column of a TreeTable.
+type Column struct {
+	Header string                // Header of the column.
+	Value  func(node any) string // Returns the value of the column for the passed node.
+	Align  Alignment             // Alignment of the column header and values.
+}
+
+// defaultTableGap is the default number of spaces between table columns.
+const defaultTableGap = 2
+
+// TreeTable renders trees as tables, with the tree in the first column and
+// data columns right of it, similar to “ps” and “pstree” output. The tree
+// column is rendered using the tree styler, as usual. The data columns then
+// get their values from the nodes on the label lines; all other lines, such
+// as property lines, don't have any values.
+//
+// Optionally, a header line with the column headers and a separator line
+// below it are rendered. The separator line uses the node connector of the
+// tree style.
+//
+// As all columns need to be lined up, TreeTable renders the whole tree before
+// producing the first line.
+type TreeTable struct {
+	TreeHeader string   // Header of the tree column.
+	Columns    []Column // Data columns right of the tree column.
+	Header     bool     // Render a header line with the column headers.
+	Separator  bool     // Render a separator line below the header line.
+	Gap        int      // Number of spaces between columns; defaults to 2 if zero.
+}
+
+// Render renders a tree (or multi-root tree) as a table into a multi-line
+// text string, using the supplied visitor and tree styler. Render panics when
+// encountering unsupported roots or nodes; use TryRender instead to get an
+// error in these situations. Like the Render function, Render ignores
+// properties and attributes of map nodes of an unsupported type.
+func (t *TreeTable) Render(roots any, visitor Visitor, styler *TreeStyler) string {
+	var result strings.Builder
+	if err := t.render(&result, roots, visitor, styler.plain(nil), true); err != nil {
+		panic(err)
+	}
+	return result.String()
+}
+
+// TryRender works like Render, but returns an error instead of panicking when
+// encountering unsupported roots or nodes.
+func (t *TreeTable) TryRender(roots any, visitor Visitor, styler *TreeStyler) (string, error) {
+	var result strings.Builder
+	if err := t.render(&result, roots, visitor, styler.plain(nil), false); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// RenderTo works like TryRender, but writes the rendered table into the passed
+// writer, line by line. RenderTo stops at the first write error or
+// unsupported roots or node and returns the error.
+func (t *TreeTable) RenderTo(w io.Writer, roots any, visitor Visitor, styler *TreeStyler) error {
+	return t.render(w, roots, visitor, styler.plain(w), false)
+}
+
+// render renders the table rows into the passed writer, line by line,
+// optionally tolerating bad user data as the panicking API always did.
+func (t *TreeTable) render(w io.Writer, roots any, visitor Visitor, styler *TreeStyler, lenient bool) error {
+	r := newRenderer(visitor, styler)
+	r.lenient = lenient
+	lines, err := r.forest(roots)
+	if err != nil {
+		return err
+	}
+	// first render all rows in order to learn the column widths.
+	widths := make([]int, 1+len(t.Columns))
+	measure := func(row []string) {
+		for idx, cell := range row {
+			widths[idx] = max(widths[idx], StringWidth(cell))
+		}
+	}
+	var header []string
+	if t.Header {
+		header = append(header, t.TreeHeader)
+		for _, col := range t.Columns {
+			header = append(header, col.Header)
+		}
+		measure(header)
+	}
+	var rows [][]string
+	for line, info := range lines {
+		row := []string{line}
+		if info.Kind == LabelLine && !info.Continuation {
+			for _, col := range t.Columns {
+				value := ""
+				if col.Value != nil {
+					value = col.Value(info.Node)
+				}
+				row = append(row, value)
+			}
+		}
+		// measure all lines, as property, continuation, and marker lines
+		// might be wider than the label lines.
+		measure(row)
+		rows = append(rows, row)
+	}
+	if r.err != nil {
+		return r.err
+	}
+	// now produce the rows with their cells lined up.
+	gap := t.Gap
+	if gap == 0 {
+		gap = defaultTableGap
+	}
+	var buf []byte
+	write := func(row []string) error {
+		// leave out empty trailing cells, so we don't pad them.
+		for len(row) > 1 && row[len(row)-1] == "" {
+			row = row[:len(row)-1]
+		}
+		buf = buf[:0]
+		for idx, cell := range row {
+			if idx > 0 {
+				buf = append(buf, repeat(" ", gap)...)
+			}
+			padding := repeat(" ", widths[idx]-StringWidth(cell))
+			if idx > 0 && t.Columns[idx-1].Align == AlignRight {
+				buf = append(append(buf, padding...), cell...)
+				continue
+			}
+			buf = append(buf, cell...)
+			if idx < len(row)-1 {
+				buf = append(buf, padding...)
+			}
+		}
+		buf = append(buf, '\n')
+		_, err := w.Write(buf)
+		return err
+	}
+	if t.Header {
+		if err := write(header); err != nil {
+			return err
+		}
+		if t.Separator {
+			if err := write(t.separator(widths, styler)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, row := range rows {
+		if err := write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// separator returns the cells of a separator line for columns of the passed
+// widths, drawn using the node connector of the tree style.
+func (t *TreeTable) separator(widths []int, styler *TreeStyler) []string {
+	conn := styler.Style.Nodeconn
+	connWidth := max(StringWidth(conn), 1)
+	cells := make([]string, len(widths))
+	for idx, width := range widths {
+		cells[idx] = styler.BranchStyle.Apply(repeat(conn, width/connWidth))
+	}
+	return cells
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"bytes"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("tree tables", func() {
+
+	type M map[string]any
+
+	tree := M{"label": "root", "size": 12345, "properties": []string{"prop"}, "children": []M{
+		{"label": "a", "size": 1},
+		{"label": "bb", "size": 42},
+	}}
+
+	size := Column{Header: "SIZE", Align: AlignRight, Value: func(node any) string {
+		return fmt.Sprint(node.(M)["size"])
+	}}
+	label := Column{Header: "NAME", Value: func(node any) string {
+		return node.(M)["label"].(string)
+	}}
+
+	It("renders only the tree without columns", func() {
+		table := &TreeTable{}
+		Expect(table.Render(tree, DefaultVisitor, DefaultTreeStyler)).To(Equal(
+			Render(tree, DefaultVisitor, DefaultTreeStyler)))
+	})
+
+	It("renders aligned columns", func() {
+		table := &TreeTable{Columns: []Column{size, label}}
+		Expect(table.Render(tree, DefaultVisitor, DefaultTreeStyler)).To(Equal(`root       12345  root
+|  * prop
++- a           1  a
+` + "`" + `- bb         42  bb
+`))
+	})
+
+	It("renders headers and separators", func() {
+		table := &TreeTable{
+			TreeHeader: "TREE",
+			Columns:    []Column{label, size, {Header: "NONE"}},
+			Header:     true,
+			Separator:  true,
+			Gap:        1,
+		}
+		Expect(table.Render(tree, DefaultVisitor, DefaultTreeStyler)).To(Equal(`TREE      NAME  SIZE NONE
+--------- ---- ----- ----
+root      root 12345
+|  * prop
++- a      a        1
+` + "`" + `- bb     bb      42
+`))
+	})
+
+	It("lines up columns right of property lines wider than labels", func() {
+		table := &TreeTable{Columns: []Column{size}}
+		Expect(table.Render(M{"label": "init", "size": 1, "properties": []string{"cmdline: /sbin/init --verbose"}},
+			DefaultVisitor, DefaultTreeStyler)).To(Equal(`init                                1
+   * cmdline: /sbin/init --verbose
+`))
+	})
+
+	It("reports errors", func() {
+		table := &TreeTable{Columns: []Column{size}}
+		_, err := table.TryRender(42, DefaultVisitor, DefaultTreeStyler)
+		Expect(err).To(MatchError(ErrUnsupportedRootsType))
+		_, err = table.TryRender(M{"label": "root", "children": []any{42}}, DefaultVisitor, DefaultTreeStyler)
+		Expect(err).To(MatchError(ErrUnsupportedNodeType))
+		Expect(func() { table.Render(42, DefaultVisitor, DefaultTreeStyler) }).To(
+			PanicWith(MatchError(ErrUnsupportedRootsType)))
+		Expect(table.RenderTo(&failingWriter{}, tree, DefaultVisitor, DefaultTreeStyler)).To(
+			MatchError(errWriteFailed))
+	})
+
+	It("renders into a writer", func() {
+		table := &TreeTable{Columns: []Column{size}, Header: true}
+		var buff bytes.Buffer
+		Expect(table.RenderTo(&buff, M{"label": "root", "size": 1}, DefaultVisitor, DefaultTreeStyler)).To(Succeed())
+		Expect(buff.String()).To(Equal("      SIZE\nroot     1\n"))
+	})
+
+})
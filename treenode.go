@@ -0,0 +1,53 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"reflect"
+)
+
+// TreeNode can be implemented by user types in order to directly provide
+// their tree-relevant data to a MapStructVisitor, instead of using tagged
+// struct fields or maps with well-known keys. This allows rendering types
+// that cannot be tagged, such as types from other packages, using wrapper
+// types with these methods.
+//
+// A MapStructVisitor detects TreeNode implementations before falling back to
+// tagged fields and well-known map keys, including implementations with
+// pointer receivers on nodes passed by value.
+type TreeNode interface {
+	AsciitreeLabel() string
+	AsciitreeProperties() []string
+	AsciitreeChildren() []any
+}
+
+var treeNodeType = reflect.TypeFor[TreeNode]()
+
+// treeNode returns the passed node as a TreeNode if either the node itself or
+// a pointer to it implements TreeNode. In the latter case, the TreeNode
+// methods get called on a copy of the node.
+func treeNode(node any) (TreeNode, bool) {
+	if tn, ok := node.(TreeNode); ok {
+		return tn, true
+	}
+	v := reflect.ValueOf(node)
+	if !v.IsValid() || v.Kind() == reflect.Pointer ||
+		!reflect.PointerTo(v.Type()).Implements(treeNodeType) {
+		return nil, false
+	}
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	return p.Interface().(TreeNode), true
+}
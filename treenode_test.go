@@ -0,0 +1,121 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// dirNode is a TreeNode with value receivers that is neither a struct nor a
+// map.
+type dirNode string
+
+func (d dirNode) AsciitreeLabel() string        { return string(d) }
+func (d dirNode) AsciitreeProperties() []string { return []string{"dir"} }
+func (d dirNode) AsciitreeChildren() []any {
+	if strings.Count(string(d), "/") > 1 {
+		return nil
+	}
+	return []any{d + "/b", d + "/a"}
+}
+
+// wrappedNode is a TreeNode with pointer receivers that takes precedence over
+// its tagged fields.
+type wrappedNode struct {
+	Name       string            `asciitree:"label"`
+	Attributes map[string]string `asciitree:"attributes"`
+	Annotation string            `asciitree:"annotation"`
+	Children   []wrappedNode     `asciitree:"children"`
+}
+
+func (w *wrappedNode) AsciitreeLabel() string        { return strings.ToUpper(w.Name) }
+func (w *wrappedNode) AsciitreeProperties() []string { return []string{"z", "a"} }
+func (w *wrappedNode) AsciitreeChildren() []any {
+	children := make([]any, len(w.Children))
+	for idx, child := range w.Children {
+		children[idx] = child
+	}
+	return children
+}
+
+var _ = Describe("tree nodes", func() {
+
+	It("detects tree nodes", func() {
+		tn, ok := treeNode(dirNode("/"))
+		Expect(ok).To(BeTrue())
+		Expect(tn.AsciitreeLabel()).To(Equal("/"))
+
+		tn, ok = treeNode(wrappedNode{Name: "foo"})
+		Expect(ok).To(BeTrue())
+		Expect(tn.AsciitreeLabel()).To(Equal("FOO"))
+		_, ok = treeNode(&wrappedNode{Name: "foo"})
+		Expect(ok).To(BeTrue())
+
+		_, ok = treeNode(Node{})
+		Expect(ok).To(BeFalse())
+		_, ok = treeNode(&Node{})
+		Expect(ok).To(BeFalse())
+		_, ok = treeNode(nil)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("visits tree nodes with value receivers", func() {
+		Expect(DefaultVisitor.Roots(dirNode("/r"))).To(HaveExactElements(dirNode("/r")))
+		label, props, children := DefaultVisitor.Get(dirNode("/r"))
+		Expect(label).To(Equal("/r"))
+		Expect(props).To(HaveExactElements("dir"))
+		Expect(children).To(HaveExactElements(dirNode("/r/b"), dirNode("/r/a")))
+		Expect(DefaultVisitor.Attributes(dirNode("/r"))).To(BeEmpty())
+		Expect(DefaultVisitor.Annotation(dirNode("/r"))).To(BeEmpty())
+	})
+
+	It("prefers tree nodes with pointer receivers over tagged fields", func() {
+		node := wrappedNode{Name: "foo", Children: []wrappedNode{{Name: "bar"}}}
+		for _, n := range []any{node, &node} {
+			Expect(DefaultVisitor.Label(n)).To(Equal("FOO"))
+			label, props, children := DefaultVisitor.Get(n)
+			Expect(label).To(Equal("FOO"))
+			Expect(props).To(HaveExactElements("z", "a"))
+			Expect(children).To(HaveExactElements(node.Children[0]))
+		}
+	})
+
+	It("ignores tagged attributes and annotations of tree nodes", func() {
+		node := wrappedNode{Name: "foo", Attributes: map[string]string{"a": "b"}, Annotation: "note"}
+		for _, n := range []any{node, &node} {
+			Expect(DefaultVisitor.Attributes(n)).To(BeEmpty())
+			Expect(DefaultVisitor.Annotation(n)).To(BeEmpty())
+		}
+		Expect(Render(node, DefaultVisitor, DefaultTreeStyler)).To(Equal("FOO\n   * z\n   * a\n"))
+	})
+
+	It("renders sorted tree nodes", func() {
+		Expect(Render(dirNode("/r"), NewMapStructVisitor(true, true), DefaultTreeStyler)).To(Equal(`/r
+|  * dir
++- /r/a
+|     * dir
+` + "`" + `- /r/b
+      * dir
+`))
+		Expect(Render([]wrappedNode{{Name: "foo"}}, NewMapStructVisitor(true, true), DefaultTreeStyler)).To(Equal(`FOO
+   * a
+   * z
+`))
+	})
+
+})
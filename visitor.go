@@ -15,7 +15,6 @@
 package asciitree
 
 import (
-	"fmt"
 	"reflect"
 	"slices"
 	"sort"
@@ -41,6 +40,17 @@ type Visitor interface {
 	Get(node any) (label string, properties []string, children []any)
 }
 
+// ErrorVisitor is an optional extension to the Visitor interface for visitors
+// that report unsupported user data in form of errors instead of panicking.
+// The TryRender and RenderTo functions use the error-returning methods when
+// the passed visitor implements this interface.
+type ErrorVisitor interface {
+	Visitor
+	TryRoots(roots any) (children []any, err error)
+	TryLabel(node any) (label string, err error)
+	TryGet(node any) (label string, properties []string, children []any, err error)
+}
+
 // DefaultVisitor provides visitor capable of traversing (annotated) maps and
 // structs.
 var DefaultVisitor = &MapStructVisitor{}
@@ -48,14 +58,18 @@ var DefaultVisitor = &MapStructVisitor{}
 // MapStructVisitor visits tagged ("annotated") user-defined structs as well
 // as maps (the latter using well-known keys) and retrieves their
 // tree-relevant data. For convenience, it also handles slices and pointers to
-// structs and maps.
+// structs and maps. User types implementing TreeNode take precedence over
+// tagged fields and well-known map keys.
 type MapStructVisitor struct {
 	Visitor
 	SortNodes      bool
 	SortProperties bool
 }
 
-var _ Visitor = (*MapStructVisitor)(nil)
+var (
+	_ ErrorVisitor   = (*MapStructVisitor)(nil)
+	_ detailsVisitor = (*MapStructVisitor)(nil)
+)
 
 // NewMapStructVisitor creates a visitor that optionally sorts nodes and their
 // properties.
@@ -65,8 +79,21 @@ func NewMapStructVisitor(sortNodes bool, sortProperties bool) *MapStructVisitor
 
 // Roots returns the list of root nodes, while handling different types of
 // Roots data types; for instance, struct, []struct, map, and []map, as well
-// as pointers.
+// as pointers. Roots panics when the roots are of an unsupported type.
 func (v *MapStructVisitor) Roots(roots any) []any {
+	nodes, err := v.TryRoots(roots)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// TryRoots works like Roots, but returns an error instead of panicking when
+// the roots are of an unsupported type.
+func (v *MapStructVisitor) TryRoots(roots any) ([]any, error) {
+	if _, ok := treeNode(roots); ok {
+		return []any{roots}, nil
+	}
 	switch rv := reflect.Indirect(reflect.ValueOf(roots)); rv.Kind() {
 	case reflect.Slice:
 		// For a slice we need to iterate over all elements, so we return all
@@ -74,7 +101,7 @@ func (v *MapStructVisitor) Roots(roots any) []any {
 		// sort by label, then we also need to sort the roots.
 		roots := anySlice(rv)
 		if !v.SortNodes {
-			return roots
+			return roots, nil
 		}
 		return v.sortedNodes(roots)
 	case reflect.Struct:
@@ -83,20 +110,23 @@ func (v *MapStructVisitor) Roots(roots any) []any {
 		// struct itself.
 		si := structFieldInfo(rv)
 		if si.RootsPath == nil {
-			return []any{roots}
+			return []any{roots}, nil
 		}
-		return v.Roots(rv.FieldByIndex(si.RootsPath).Interface())
+		return v.TryRoots(rv.FieldByIndex(si.RootsPath).Interface())
 	case reflect.Map:
 		// Finally, roots can also be stored in a map using a well-known key
 		// named "roots". If that key is present, then it must be a list of
 		// children, otherwise return a list of children consisting only if this
 		// map itself because it's already a child.
-		maproots := rv.MapIndex(reflect.ValueOf("roots"))
+		if !hasStringKeys(rv) {
+			return nil, newNodeError(ErrUnsupportedRootsType, roots)
+		}
+		maproots := mapValue(rv, "roots")
 		switch maproots.Kind() {
 		case reflect.Invalid:
 			// Nope, no such "roots" key, so the root given is the only one root
 			// node itself, so return it as a list of exactly one root node.
-			return []any{roots}
+			return []any{roots}, nil
 		default:
 			// The roots element may be represented by map or struct, or it might
 			// be a slice of them; especially due to the latter case we have to
@@ -104,106 +134,187 @@ func (v *MapStructVisitor) Roots(roots any) []any {
 			// property. Unfortunately, we cannot simply return the slice Value
 			// itself, but instead need to create a new slice of Values
 			// referencing the elements of the original slice.
-			elem := maproots.Elem()
-			if elem.Kind() == reflect.Slice {
-				return v.Roots(elem.Interface())
+			if maproots.Kind() == reflect.Interface {
+				maproots = maproots.Elem()
+			}
+			if !maproots.IsValid() {
+				return nil, newNodeError(ErrUnsupportedRootsType, nil)
 			}
-			return []any{reflect.Indirect(maproots).Interface()}
+			if maproots.Kind() == reflect.Slice {
+				return v.TryRoots(maproots.Interface())
+			}
+			return []any{reflect.Indirect(maproots).Interface()}, nil
 		}
 	default:
-		panic(fmt.Sprintf("expecting roots to be a slice, struct, or map, but got %T", roots))
+		return nil, newNodeError(ErrUnsupportedRootsType, roots)
 	}
 }
 
-// Label returns the label for a tree node.
+// Label returns the label for a tree node. Label panics when the node is of an
+// unsupported type.
 func (v *MapStructVisitor) Label(node any) (label string) {
+	label, err := v.TryLabel(node)
+	if err != nil {
+		panic(err)
+	}
+	return label
+}
+
+// TryLabel works like Label, but returns an error instead of panicking when
+// the node is of an unsupported type.
+func (v *MapStructVisitor) TryLabel(node any) (label string, err error) {
 	return v.nodeLabel(node)
 }
 
 // Get returns the label, properties, and children of a tree node, hiding
 // pesty details about how to fetch them from tagged structs or maps with
-// well-known fields.
+// well-known fields. Get panics when the node is of an unsupported type or the
+// properties of a struct node are of an unsupported type. For backwards
+// compatibility, Get ignores properties of map nodes of an unsupported type.
+// Get returns only the texts of Property slices, leaving out any
+// sub-properties; see Properties.
 func (v *MapStructVisitor) Get(node any) (label string, properties []string, children []any) {
-	label, properties, children = v.nodeDetails(node)
+	label, properties, children, err := v.get(node, true)
+	if err != nil {
+		panic(err)
+	}
+	return label, properties, children
+}
+
+// TryGet works like Get, but returns an error instead of panicking when the
+// node is of an unsupported type or its properties (including the properties
+// of map nodes) are of an unsupported type.
+func (v *MapStructVisitor) TryGet(node any) (label string, properties []string, children []any, err error) {
+	return v.get(node, false)
+}
+
+// get returns the label, (optionally sorted) property texts, and (optionally
+// sorted) children of a tree node, optionally ignoring properties of map
+// nodes of an unsupported type.
+func (v *MapStructVisitor) get(node any, lenient bool) (label string, properties []string, children []any, err error) {
+	label, props, children, err := v.nodeDetails(node, lenient)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if children, err = v.sortedChildren(children); err != nil {
+		return "", nil, nil, err
+	}
+	properties = propertyTexts(props)
 	if v.SortProperties {
-		properties = slices.Clone(properties)
 		sort.Strings(properties)
 	}
-	return label, properties, children
+	return label, properties, children, nil
 }
 
-func (v *MapStructVisitor) nodeLabel(node any) string {
+// details returns the label, (optionally sorted) hierarchical properties
+// followed by the formatted attributes, and (optionally sorted) children of a
+// tree node in a single go. When lenient, details ignores properties and
+// attributes of map nodes of an unsupported type.
+func (v *MapStructVisitor) details(node any, lenient bool) (label string, properties []Property, children []any, err error) {
+	label, properties, children, err = v.nodeDetails(node, lenient)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if children, err = v.sortedChildren(children); err != nil {
+		return "", nil, nil, err
+	}
+	if v.SortProperties {
+		properties = sortedProperties(properties)
+	}
+	attrs, err := v.attributes(node, lenient)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if len(attrs) != 0 {
+		// never append to the properties slice, as it might be the user's
+		// own []Property slice.
+		properties = slices.Concat(properties, leafProperties(formatAttributes(attrs)))
+	}
+	return label, properties, children, nil
+}
+
+func (v *MapStructVisitor) nodeLabel(node any) (string, error) {
+	if kn, ok := node.(KeyedNode); ok {
+		return kn.Key, nil
+	}
+	if tn, ok := treeNode(node); ok {
+		return tn.AsciitreeLabel(), nil
+	}
 	switch node := reflect.Indirect(reflect.ValueOf(node)); node.Kind() {
 	case reflect.Struct:
-		si := structFieldInfo(node)
-		if si.LabelPath == nil {
-			return ""
-		}
-		return node.FieldByIndex(si.LabelPath).String()
+		return structLabel(node, structFieldInfo(node)), nil
 	case reflect.Map:
-		labelV := node.MapIndex(reflect.ValueOf("label"))
-		if labelV.Kind() == reflect.Interface {
-			labelV = labelV.Elem()
-		}
-		if labelV.Kind() != reflect.String {
-			return ""
+		if !hasStringKeys(node) {
+			return "", newNodeError(ErrUnsupportedNodeType, anyOf(node))
 		}
-		return labelV.Interface().(string)
+		return mapLabel(node), nil
 	default:
-		panic(fmt.Sprintf("unsupported asciitree node or root type %T", node.Interface()))
+		return "", newNodeError(ErrUnsupportedNodeType, anyOf(node))
 	}
 }
 
-// Internal helper to either only retrieve the label for a node, or the label,
-// properties, and children. Please note that we don't sort here; this is
-// really only the helper for retrieving.
-func (v *MapStructVisitor) nodeDetails(node any) (label string, properties []string, children []any) {
+// Internal helper to retrieve the label, hierarchical properties, and
+// children of a node. Please note that we don't sort properties and children
+// here; this is really only the helper for retrieving. When lenient,
+// properties of map nodes of an unsupported type are ignored.
+func (v *MapStructVisitor) nodeDetails(node any, lenient bool) (label string, properties []Property, children []any, err error) {
+	if kn, ok := node.(KeyedNode); ok {
+		_, properties, children, err = v.nodeDetails(kn.Node, lenient)
+		return kn.Key, properties, children, err
+	}
+	if tn, ok := treeNode(node); ok {
+		return tn.AsciitreeLabel(), leafProperties(tn.AsciitreeProperties()), tn.AsciitreeChildren(), nil
+	}
 	switch node := reflect.Indirect(reflect.ValueOf(node)); node.Kind() {
 	case reflect.Struct:
 		// Grab the values for a node label, its properties, and its children,
 		// if there are fields known to have them – based on their field
 		// tags.
 		si := structFieldInfo(node)
-		if si.LabelPath != nil {
-			label = node.FieldByIndex(si.LabelPath).String()
+		label = structLabel(node, si)
+		if properties, err = structProperties(node, si); err != nil {
+			return "", nil, nil, err
 		}
-		if si.PropertiesPath != nil {
-			properties = node.FieldByIndex(si.PropertiesPath).Interface().([]string)
+		if si.ChildrenPath != nil {
+			children = anyNodes(node.FieldByIndex(si.ChildrenPath), si.KeyLabels)
 		}
-		if si.ChildrenPath == nil {
-			return
-		}
-		children = anySlice(node.FieldByIndex(si.ChildrenPath))
-		if !v.SortNodes {
-			return
-		}
-		children = v.sortedNodes(children)
-		return
+		return label, properties, children, nil
 	case reflect.Map:
 		// Gets the (well-known) key-values for label, properties, and children in
 		// a map. Again, all these keys-values are optional and will default to
 		// zero if missing.
-		if lbl := node.MapIndex(reflect.ValueOf("label")); lbl.Kind() != reflect.Invalid {
-			label, _ = lbl.Interface().(string)
-		}
-		if pps := node.MapIndex(reflect.ValueOf("properties")); pps.Kind() != reflect.Invalid {
-			properties, _ = pps.Elem().Interface().([]string)
+		if !hasStringKeys(node) {
+			return "", nil, nil, newNodeError(ErrUnsupportedNodeType, anyOf(node))
 		}
-		if chs := node.MapIndex(reflect.ValueOf("children")); chs.Kind() != reflect.Invalid {
-			children = anySlice(chs)
-			if v.SortNodes {
-				children = v.sortedNodes(children)
+		label = mapLabel(node)
+		properties, err = propertySlice(mapValue(node, "properties"), label)
+		if err != nil {
+			if !lenient {
+				return "", nil, nil, err
 			}
+			properties = nil
+		}
+		if chs := mapValue(node, "children"); chs.Kind() != reflect.Invalid {
+			children = anyNodes(chs, false)
 		}
-		return
+		return label, properties, children, nil
 	default:
-		panic(fmt.Sprintf("unsupported asciitree node or root type %T", node.Interface()))
+		return "", nil, nil, newNodeError(ErrUnsupportedNodeType, anyOf(node))
 	}
 }
 
+// sortedChildren returns the passed children sorted by their labels if this
+// visitor sorts nodes; otherwise, it returns the children unchanged.
+func (v *MapStructVisitor) sortedChildren(children []any) ([]any, error) {
+	if !v.SortNodes || children == nil {
+		return children, nil
+	}
+	return v.sortedNodes(children)
+}
+
 // sortedNodes returns a new slice of sorted nodes from the passed slice of
 // nodes, sorted by lexicographically by their labels.
-func (v *MapStructVisitor) sortedNodes(nodes []any) []any {
+func (v *MapStructVisitor) sortedNodes(nodes []any) ([]any, error) {
 	type labelledNode struct {
 		Label string
 		Node  any
@@ -211,7 +322,11 @@ func (v *MapStructVisitor) sortedNodes(nodes []any) []any {
 	l := len(nodes)
 	labelledNodes := make([]labelledNode, l)
 	for idx := range nodes {
-		labelledNodes[idx] = labelledNode{Label: v.Label(nodes[idx]), Node: nodes[idx]}
+		label, err := v.nodeLabel(nodes[idx])
+		if err != nil {
+			return nil, err
+		}
+		labelledNodes[idx] = labelledNode{Label: label, Node: nodes[idx]}
 	}
 	slices.SortStableFunc(labelledNodes, func(a, b labelledNode) int {
 		return strings.Compare(a.Label, b.Label)
@@ -220,7 +335,7 @@ func (v *MapStructVisitor) sortedNodes(nodes []any) []any {
 	for idx := range l {
 		sortednodes[idx] = labelledNodes[idx].Node
 	}
-	return sortednodes
+	return sortednodes, nil
 }
 
 // anySlice returns an []any value whose elements are the slice elements
@@ -240,3 +355,31 @@ func anySlice(v reflect.Value) []any {
 	}
 	return anyslice
 }
+
+// hasStringKeys returns true if the passed map can be indexed using the
+// well-known string keys, such as “label”; that is, its key type is a string
+// type or an interface type implemented by strings.
+func hasStringKeys(m reflect.Value) bool {
+	return stringType.ConvertibleTo(m.Type().Key())
+}
+
+var stringType = reflect.TypeFor[string]()
+
+// mapValue returns the value of the passed well-known key in the passed map,
+// or an invalid reflect.Value if the key is missing or the map cannot be
+// indexed using string keys.
+func mapValue(m reflect.Value, key string) reflect.Value {
+	if !hasStringKeys(m) {
+		return reflect.Value{}
+	}
+	return m.MapIndex(reflect.ValueOf(key).Convert(m.Type().Key()))
+}
+
+// anyOf returns the interface value of the passed reflect.Value, or nil if the
+// reflect.Value is invalid, such as when it was created from a nil value.
+func anyOf(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
@@ -185,7 +185,8 @@ var _ = Describe("visiting", func() {
 
 		It("retrieves 'children' nodes and sorts them by their 'label's", func() {
 			_, _, children := DefaultVisitor.Get(testMap)
-			sortedChildren := DefaultVisitor.sortedNodes(children)
+			sortedChildren, err := DefaultVisitor.sortedNodes(children)
+			Expect(err).NotTo(HaveOccurred())
 			Expect(sortedChildren).ToNot(Equal(children))
 			Expect(sortedChildren).To(HaveLen(3))
 			label := DefaultVisitor.Label(sortedChildren[0])
@@ -195,10 +196,77 @@ var _ = Describe("visiting", func() {
 	})
 
 	It("panics when presented with neither struct nor map", func() {
-		Expect(func() { _, _, _ = DefaultVisitor.nodeDetails(42) }).To(
-			PanicWith(MatchRegexp(`unsupported asciitree node.*type int`)))
-		Expect(func() { _ = DefaultVisitor.nodeLabel(42) }).To(
-			PanicWith(MatchRegexp(`unsupported asciitree node.*type int`)))
+		Expect(func() { _, _, _ = DefaultVisitor.Get(42) }).To(
+			PanicWith(MatchError(MatchRegexp(`unsupported asciitree node.*type int`))))
+		Expect(func() { _ = DefaultVisitor.Label(42) }).To(
+			PanicWith(MatchError(MatchRegexp(`unsupported asciitree node.*type int`))))
+	})
+
+	When("reporting errors instead of panicking", func() {
+
+		It("reports unsupported node types", func() {
+			_, _, _, err := DefaultVisitor.TryGet(42)
+			Expect(err).To(MatchError(ErrUnsupportedNodeType))
+			Expect(err).To(BeAssignableToTypeOf(&NodeError{}))
+			Expect(err.(*NodeError).Type).To(Equal(reflect.TypeOf(42)))
+
+			_, err = DefaultVisitor.TryLabel(nil)
+			Expect(err).To(MatchError(ErrUnsupportedNodeType))
+			Expect(err.(*NodeError).Type).To(BeNil())
+		})
+
+		It("reports unsupported child node types when sorting", func() {
+			type M map[string]any
+			_, _, _, err := NewMapStructVisitor(true, false).TryGet(M{
+				"children": []any{M{"label": "foo"}, 42},
+			})
+			Expect(err).To(MatchError(ErrUnsupportedNodeType))
+		})
+
+		It("reports bad properties", func() {
+			type T struct {
+				Label string `asciitree:"label"`
+				Props []int  `asciitree:"properties"`
+			}
+			_, _, _, err := DefaultVisitor.TryGet(T{Label: "foo", Props: []int{42}})
+			Expect(err).To(MatchError(ErrBadPropertiesField))
+			Expect(err).To(And(
+				HaveField("Type", reflect.TypeOf([]int{})),
+				HaveField("Path", HaveExactElements("foo"))))
+			Expect(func() { _, _, _ = DefaultVisitor.Get(T{}) }).To(
+				PanicWith(MatchError(ErrBadPropertiesField)))
+
+			_, _, _, err = DefaultVisitor.TryGet(map[string]any{"properties": 42})
+			Expect(err).To(MatchError(ErrBadPropertiesField))
+
+			label, props, _ := DefaultVisitor.Get(map[string]any{"label": "foo", "properties": 42})
+			Expect(label).To(Equal("foo"))
+			Expect(props).To(BeEmpty())
+		})
+
+		It("reports maps without string keys", func() {
+			node := map[int]string{1: "a"}
+			_, err := DefaultVisitor.TryLabel(node)
+			Expect(err).To(MatchError(ErrUnsupportedNodeType))
+			_, _, _, err = DefaultVisitor.TryGet(node)
+			Expect(err).To(MatchError(ErrUnsupportedNodeType))
+			_, err = DefaultVisitor.Properties(node)
+			Expect(err).To(MatchError(ErrUnsupportedNodeType))
+			_, err = DefaultVisitor.Attributes(node)
+			Expect(err).To(MatchError(ErrUnsupportedNodeType))
+			_, err = DefaultVisitor.Annotation(node)
+			Expect(err).To(MatchError(ErrUnsupportedNodeType))
+			_, err = DefaultVisitor.TryRoots(node)
+			Expect(err).To(MatchError(ErrUnsupportedRootsType))
+		})
+
+		It("reports unsupported roots", func() {
+			_, err := DefaultVisitor.TryRoots(42)
+			Expect(err).To(MatchError(ErrUnsupportedRootsType))
+			_, err = NewMapStructVisitor(true, false).TryRoots([]any{42})
+			Expect(err).To(MatchError(ErrUnsupportedNodeType))
+		})
+
 	})
 
 	When("working with roots", func() {
@@ -273,6 +341,18 @@ var _ = Describe("visiting", func() {
 					Equal(tree)))
 			})
 
+			It("handles a typed roots map", func() {
+				type T map[string]any
+
+				roots := map[string][]T{"roots": {{"label": "a"}, {"label": "b"}}}
+				Expect(DefaultVisitor.TryRoots(roots)).To(HaveExactElements(
+					T{"label": "a"}, T{"label": "b"}))
+				root := map[string]T{"roots": {"label": "a"}}
+				Expect(DefaultVisitor.TryRoots(root)).To(HaveExactElements(T{"label": "a"}))
+				_, err := DefaultVisitor.TryRoots(map[string]any{"roots": nil})
+				Expect(err).To(MatchError(ErrUnsupportedRootsType))
+			})
+
 			It("handles a map with a roots slice value", func() {
 				type T map[string]any
 
@@ -303,7 +383,7 @@ var _ = Describe("visiting", func() {
 
 		It("panics when presented with anything else than slice, struct, map", func() {
 			Expect(func() { _ = DefaultVisitor.Roots(42) }).To(
-				PanicWith(MatchRegexp(`but got int`)))
+				PanicWith(MatchError(MatchRegexp(`unsupported asciitree roots type int`))))
 		})
 
 	})
@@ -0,0 +1,97 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+// Special runes that influence the display width of their neighbors.
+const (
+	zeroWidthJoiner    = '\u200d'
+	variationSelector  = '\ufe0f' // requests emoji presentation of the preceding rune.
+	emojiModifierFirst = '\U0001f3fb'
+	emojiModifierLast  = '\U0001f3ff'
+)
+
+// RuneWidth returns the number of terminal columns needed to display the
+// passed rune on its own: 0 for control characters, combining marks, and
+// other zero-width characters, 2 for East Asian wide and fullwidth characters
+// (which includes most emoji), and 1 otherwise.
+func RuneWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case r >= 0x1160 && r <= 0x11ff:
+		// Hangul Jamo medial vowels and final consonants combine with the
+		// preceding initial consonant into a single syllable.
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// StringWidth returns the number of terminal columns needed to display the
+// passed string. In contrast to simply summing up the individual RuneWidth's,
+// StringWidth takes emoji sequences into account: runes joined using a
+// zero-width joiner as well as emoji modifiers don't take up additional
+// columns, while a variation selector requesting emoji presentation widens
+// the preceding narrow rune. ANSI escape sequences, such as for colors, don't
+// take up any columns either.
+func StringWidth(s string) int {
+	total := 0
+	prevWidth := 0
+	joined := false
+	skip := 0 // index of the first byte after an escape sequence.
+	for idx, r := range s {
+		switch {
+		case idx < skip:
+			continue
+		case r == escape:
+			skip = idx + escapeLen(s[idx:])
+			continue
+		case joined:
+			// the rune after a zero-width joiner becomes part of the
+			// preceding emoji (sequence), so it doesn't take up any space
+			// on its own.
+			joined = false
+			continue
+		case r == zeroWidthJoiner:
+			joined = prevWidth != 0
+			continue
+		case r == variationSelector:
+			if prevWidth == 1 {
+				total++
+				prevWidth = 2
+			}
+			continue
+		case r >= emojiModifierFirst && r <= emojiModifierLast && prevWidth != 0:
+			continue
+		}
+		w := RuneWidth(r)
+		if w != 0 {
+			prevWidth = w
+		}
+		total += w
+	}
+	return total
+}
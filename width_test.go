@@ -0,0 +1,60 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("display width", func() {
+
+	DescribeTable("single runes",
+		func(r rune, expected int) {
+			Expect(RuneWidth(r)).To(Equal(expected))
+		},
+		Entry("ASCII", 'a', 1),
+		Entry("control", '\t', 0),
+		Entry("C1 control", '\u0085', 0),
+		Entry("combining acute accent", '\u0301', 0),
+		Entry("zero-width space", '\u200b', 0),
+		Entry("Hangul medial vowel", '\u1161', 0),
+		Entry("box drawing", '├', 1),
+		Entry("CJK ideograph", '世', 2),
+		Entry("fullwidth letter", 'Ａ', 2),
+		Entry("Hangul syllable", '한', 2),
+		Entry("emoji", '😀', 2),
+	)
+
+	DescribeTable("strings",
+		func(s string, expected int) {
+			Expect(StringWidth(s)).To(Equal(expected))
+		},
+		Entry("empty", "", 0),
+		Entry("ASCII", "foo bar", 7),
+		Entry("CJK", "世界", 4),
+		Entry("mixed", "a世b", 4),
+		Entry("combining marks", "e\u0301e\u0301", 2),
+		Entry("ZWJ emoji sequence", "👩\u200d💻", 2),
+		Entry("family ZWJ sequence", "👨\u200d👩\u200d👧", 2),
+		Entry("leading ZWJ", "\u200da", 1),
+		Entry("emoji modifier", "👍\U0001f3fd", 2),
+		Entry("lone emoji modifier", "\U0001f3fd", 2),
+		Entry("emoji presentation selector", "❤\ufe0f", 2),
+		Entry("emoji presentation selector on wide", "😀\ufe0f", 2),
+		Entry("regional indicators flag", "🇩🇪", 2),
+	)
+
+})
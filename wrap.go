@@ -0,0 +1,165 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ellipsis marks truncated text.
+const ellipsis = "…"
+
+// splitLines splits the passed text into its individual lines, ignoring any
+// trailing line breaks.
+func splitLines(text string) []string {
+	return strings.Split(strings.ReplaceAll(strings.TrimRight(text, "\r\n"), "\r\n", "\n"), "\n")
+}
+
+// wrapLine word-wraps the passed single line of text so that each resulting
+// line is at most width columns wide. Words too long to fit on a line of
+// their own are broken. Spaces at line breaks are dropped, while leading
+// spaces are kept.
+func wrapLine(line string, width int) []string {
+	if width <= 0 || StringWidth(line) <= width {
+		return []string{line}
+	}
+	var lines []string
+	var current strings.Builder
+	currentWidth := 0
+	empty := true // nothing yet on the current line
+	flush := func() {
+		lines = append(lines, strings.TrimRight(current.String(), " "))
+		current.Reset()
+		currentWidth = 0
+		empty = true
+	}
+	for _, word := range strings.Split(line, " ") {
+		wordWidth := StringWidth(word)
+		if !empty && currentWidth+1+wordWidth > width {
+			flush()
+			if word == "" {
+				continue
+			}
+		}
+		if !empty {
+			current.WriteByte(' ')
+			currentWidth++
+		}
+		// break words that are too long to fit on a line of their own.
+		broken := false
+		for currentWidth+wordWidth > width {
+			head, tail := splitWidth(word, width-currentWidth)
+			if head == "" && currentWidth == 0 {
+				// ensure progress even if a single character is too wide.
+				_, size := utf8.DecodeRuneInString(word)
+				head, tail = word[:size], word[size:]
+			}
+			current.WriteString(head)
+			flush()
+			word = tail
+			wordWidth = StringWidth(word)
+			broken = true
+		}
+		current.WriteString(word)
+		currentWidth += wordWidth
+		empty = broken && word == ""
+	}
+	if !empty {
+		flush()
+	}
+	return lines
+}
+
+// truncateLine truncates the passed single line of text to the specified
+// width, marking truncation with an ellipsis.
+func truncateLine(line string, width int) string {
+	if width <= 0 || StringWidth(line) <= width {
+		return line
+	}
+	head, _ := splitWidth(line, width-StringWidth(ellipsis))
+	return head + ellipsis
+}
+
+// splitWidth splits the passed text into a head that is at most width columns
+// wide and the remaining tail. Zero-width characters following the last
+// character fitting into the head are kept in the head. splitWidth never
+// splits ANSI escape sequences.
+func splitWidth(text string, width int) (head string, tail string) {
+	cut := 0
+	skip := 0 // index of the first byte after an escape sequence.
+	for idx, r := range text {
+		if idx < skip {
+			continue
+		}
+		if idx > 0 && StringWidth(text[:idx]) > width {
+			return text[:cut], text[cut:]
+		}
+		cut = idx
+		if r == escape {
+			skip = idx + escapeLen(text[idx:])
+		}
+	}
+	if StringWidth(text) <= width {
+		return text, ""
+	}
+	return text[:cut], text[cut:]
+}
+
+// carryStyles carries text styles in form of ANSI SGR escape sequences across
+// the passed lines that were split, wrapped, or truncated from a single styled
+// text: each line with text styles still in effect at its end gets these text
+// styles reset, with the following line getting them reapplied at its
+// beginning. This way, text styles neither spill into the branches and
+// indentation of the following lines, nor get lost on continuation lines.
+func carryStyles(lines []string) []string {
+	active := "" // SGR escape sequences in effect at the end of the previous line.
+	for idx, line := range lines {
+		if active == "" && strings.IndexByte(line, escape) < 0 {
+			continue
+		}
+		line = active + line
+		active = activeStyles(line)
+		if active != "" {
+			line += resetStyles
+		}
+		lines[idx] = line
+	}
+	return lines
+}
+
+// activeStyles returns the SGR escape sequences still in effect at the end of
+// the passed text, that is, after the last reset.
+func activeStyles(text string) string {
+	active := ""
+	for {
+		idx := strings.IndexByte(text, escape)
+		if idx < 0 {
+			return active
+		}
+		text = text[idx:]
+		seq := text[:escapeLen(text)]
+		text = text[len(seq):]
+		if !strings.HasPrefix(seq, "\x1b[") || !strings.HasSuffix(seq, "m") {
+			continue // not an SGR escape sequence.
+		}
+		switch seq {
+		case "\x1b[m", resetStyles:
+			active = ""
+		default:
+			active += seq
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2018 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asciitree
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("wrapping", func() {
+
+	DescribeTable("splitting into lines",
+		func(text string, expected []string) {
+			Expect(splitLines(text)).To(Equal(expected))
+		},
+		Entry(nil, "", []string{""}),
+		Entry(nil, "foo", []string{"foo"}),
+		Entry(nil, "foo\nbar\n", []string{"foo", "bar"}),
+		Entry(nil, "foo\r\nbar", []string{"foo", "bar"}),
+	)
+
+	DescribeTable("splitting by width",
+		func(text string, width int, head, tail string) {
+			h, t := splitWidth(text, width)
+			Expect(h).To(Equal(head))
+			Expect(t).To(Equal(tail))
+		},
+		Entry(nil, "abc", 2, "ab", "c"),
+		Entry(nil, "abc", 3, "abc", ""),
+		Entry(nil, "abc", 0, "", "abc"),
+		Entry(nil, "ab\u0301c", 2, "ab\u0301", "c"),
+		Entry(nil, "a世b", 2, "a", "世b"),
+		Entry(nil, "世界", 3, "世", "界"),
+		Entry(nil, "ab\x1b[31mc", 2, "ab\x1b[31m", "c"),
+	)
+
+	DescribeTable("wrapping lines",
+		func(text string, width int, expected []string) {
+			Expect(wrapLine(text, width)).To(Equal(expected))
+		},
+		Entry("fits", "foo bar", 7, []string{"foo bar"}),
+		Entry("unlimited", "foo bar", 0, []string{"foo bar"}),
+		Entry("at spaces", "foo bar baz", 7, []string{"foo bar", "baz"}),
+		Entry("drops spaces at breaks", "foo   bar", 4, []string{"foo", "bar"}),
+		Entry("keeps leading spaces", "  foo bar", 6, []string{"  foo", "bar"}),
+		Entry("breaks long words", "foo abcdefghij", 4, []string{"foo", "abcd", "efgh", "ij"}),
+		Entry("moves long words to the next line first", "ab cdefgh", 5, []string{"ab", "cdefg", "h"}),
+		Entry("wide characters", "世界 世界世界", 5, []string{"世界", "世界", "世界"}),
+		Entry("too-wide characters", "世界", 1, []string{"世", "界"}),
+	)
+
+	DescribeTable("truncating lines",
+		func(text string, width int, expected string) {
+			Expect(truncateLine(text, width)).To(Equal(expected))
+		},
+		Entry(nil, "foo bar", 7, "foo bar"),
+		Entry(nil, "foo bar", 0, "foo bar"),
+		Entry(nil, "foo bar", 5, "foo …"),
+		Entry(nil, "世界世界", 5, "世界…"),
+	)
+
+	DescribeTable("carrying text styles across lines",
+		func(lines []string, expected []string) {
+			Expect(carryStyles(lines)).To(Equal(expected))
+		},
+		Entry("unstyled", []string{"foo", "bar"}, []string{"foo", "bar"}),
+		Entry("balanced", []string{"\x1b[31mfoo\x1b[0m", "bar"}, []string{"\x1b[31mfoo\x1b[0m", "bar"}),
+		Entry("spanning lines",
+			[]string{"\x1b[1m\x1b[31mfoo", "bar", "baz\x1b[m qux"},
+			[]string{"\x1b[1m\x1b[31mfoo\x1b[0m", "\x1b[1m\x1b[31mbar\x1b[0m", "\x1b[1m\x1b[31mbaz\x1b[m qux"}),
+		Entry("truncated", []string{"\x1b[31mfoo…"}, []string{"\x1b[31mfoo…\x1b[0m"}),
+		Entry("non-SGR sequences", []string{"\x1b]8;;url\afoo"}, []string{"\x1b]8;;url\afoo"}),
+	)
+
+})